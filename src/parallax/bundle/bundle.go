@@ -0,0 +1,23 @@
+// Package bundle compiles the FCTP instances in bundle/data (.DAT or .DAT.gz
+// files) into the binary, served by the embed:// data scheme:
+//
+//	import _ "parallax/bundle"
+//	./bin/player -data embed://
+package bundle
+
+import (
+	"embed"
+	"io/fs"
+	"parallax/fct"
+)
+
+//go:embed data
+var data embed.FS
+
+func init() {
+	fsys, err := fs.Sub(data, "data")
+	if err != nil {
+		panic(err)
+	}
+	fct.RegisterBundle(fsys)
+}
@@ -0,0 +1,16 @@
+package bundle
+
+import (
+	"parallax/fct"
+	"testing"
+)
+
+func TestBundle(t *testing.T) {
+	d, err := fct.OpenLoader("embed://", 0, 0)
+	if err != nil {
+		t.Fatal("Error opening bundle:", err)
+	}
+	if g := d.Instance("N104"); g == nil || g.Size() != 100 {
+		t.Error("Wrong bundled instance N104:", g)
+	}
+}
@@ -0,0 +1,164 @@
+package conformance
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"parallax/core"
+	"parallax/fct"
+	"sort"
+)
+
+// Test instances - generated (seeded) and bundled (embed://)
+
+// Generate is a balanced complete instance, always feasible (the northwest
+// corner flow fits the capacities min(supply, demand)).
+func Generate(seed int64, sources, sinks int) *fct.Graph {
+	r := rand.New(rand.NewSource(seed))
+	g := fct.NewGraph()
+	total := 0.
+	for i := 1; i <= sources; i++ {
+		s := float64(1 + r.Intn(100))
+		total += s
+		g.SourceSize(i, s)
+	}
+	for j := 0; j < sinks; j++ {
+		d := math.Floor(total / float64(sinks-j))
+		if j == sinks-1 {
+			d = total
+		}
+		total -= d
+		g.SinkSize(sources+j+1, d)
+	}
+	for i := 1; i <= sources; i++ {
+		for j := sources + 1; j <= sources+sinks; j++ {
+			g.NewEdge(i, j, float64(1+r.Intn(20)), float64(r.Intn(200)))
+		}
+	}
+	return g
+}
+
+// Bounded is a generated instance with arc bounds around the northwest
+// corner flow (still feasible) and the arcs off that flow partly removed.
+func Bounded(seed int64, sources, sinks int) *fct.Graph {
+	g := Generate(seed, sources, sinks)
+	r := rand.New(rand.NewSource(seed + 1))
+	flow := northwestCorner(g)
+	for _, e := range append([]*fct.Edge(nil), g.Edges...) {
+		key := fct.Key{Source: e.I.Data.Id, Sink: e.J.Data.Id}
+		x, used := flow[key]
+		switch {
+		case used:
+			lower := math.Floor(x * r.Float64())
+			g.SetBounds(key.Source, key.Sink, lower, x+float64(r.Intn(10)))
+		case r.Intn(3) == 0:
+			g.RemoveEdge(key.Source, key.Sink)
+		}
+	}
+	return g
+}
+
+func northwestCorner(g *fct.Graph) map[fct.Key]float64 {
+	sources := sortedSizes(g.Sources)
+	sinks := sortedSizes(g.Sinks)
+	result := make(map[fct.Key]float64)
+	for i, j := 0, 0; i < len(sources) && j < len(sinks); {
+		x := math.Min(sources[i].size, sinks[j].size)
+		result[fct.Key{Source: sources[i].id, Sink: sinks[j].id}] = x
+		sources[i].size -= x
+		sinks[j].size -= x
+		if sources[i].size <= 0 {
+			i++
+		} else {
+			j++
+		}
+	}
+	return result
+}
+
+type idSize struct {
+	id   int
+	size float64
+}
+
+func sortedSizes(m map[int]*fct.Vertex) []*idSize {
+	result := make([]*idSize, 0, len(m))
+	for id, v := range m {
+		result = append(result, &idSize{id, v.Data.Size})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].id < result[j].id })
+	return result
+}
+
+// Optimum is the FCTP optimum (fixed costs included), the best transport
+// flow (SSP) over every arc set: 2^edges solves, small instances only.
+func Optimum(g *fct.Graph) float64 {
+	best := math.Inf(1)
+	solver := core.NewSSPSolver()
+	for mask := 1; mask < 1<<uint(g.Size()); mask++ {
+		o := g.Overlay()
+		for i, e := range g.Edges {
+			if mask&(1<<uint(i)) == 0 {
+				o.Disable(e.I.Data.Id, e.J.Data.Id)
+			}
+		}
+		if flow, err := solver.ComputeFlow(o); err == nil {
+			best = math.Min(best, core.Objective(g, flow))
+		}
+	}
+	return best
+}
+
+// Overlay changes some costs (negative included) of a feasible instance.
+func Overlay(seed int64, g *fct.Graph) *fct.Graph {
+	r := rand.New(rand.NewSource(seed))
+	o := g.Overlay()
+	o.ScaleCosts(1. + r.Float64())
+	for _, e := range g.Edges {
+		if r.Intn(4) == 0 {
+			o.SetCost(e.I.Data.Id, e.J.Data.Id, float64(r.Intn(40)-10)+.25)
+		}
+	}
+	return o
+}
+
+// Cases are n generated instances of growing size (complete, bounded and
+// overlays) from the seed.
+func Cases(seed int64, n int) []*Case {
+	result := make([]*Case, 0, n)
+	for k := 0; k < n; k++ {
+		s := seed + int64(k)
+		sources, sinks := 2+k%9, 2+(3*k)%13
+		var g *fct.Graph
+		var name string
+		switch k % 3 {
+		case 0:
+			g, name = Generate(s, sources, sinks), "complete"
+		case 1:
+			g, name = Bounded(s, sources, sinks), "bounded"
+		default:
+			g, name = Overlay(s, Generate(s, sources, sinks)), "overlay"
+		}
+		result = append(result, &Case{fmt.Sprintf("%s-%d-%dx%d", name, s, sources, sinks), g})
+	}
+	return result
+}
+
+// Bundled cases are the embedded instances (import _ "parallax/bundle") and
+// their overlays, nil if there is no bundle.
+func Bundled(seed int64) ([]*Case, error) {
+	graphs, err := fct.OpenLoader("embed://", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	m, err := graphs.LoadAll(1)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Case, 0)
+	for _, info := range m.Instances {
+		g := graphs.Instance(info.Name)
+		result = append(result, &Case{info.Name, g}, &Case{info.Name + "-overlay", Overlay(seed, g)})
+	}
+	return result, nil
+}
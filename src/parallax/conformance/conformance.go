@@ -0,0 +1,125 @@
+// Package conformance checks core.Solver implementations: every solver must
+// return a feasible flow (conservation, bounds, enabled edges) with the same
+// linear cost as a reference solver, over generated and bundled instances.
+// Instances of failed cases are saved to disk (FCTP format) to reproduce them.
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"parallax/core"
+	"parallax/fct"
+	"path/filepath"
+	"strings"
+)
+
+type Case struct {
+	Name  string
+	Graph *fct.Graph
+}
+
+type Failure struct {
+	Case    string
+	Message string
+	Path    string // saved instance, empty if not saved
+}
+
+func (f *Failure) String() string {
+	out := f.Case + ": " + f.Message
+	if f.Path != "" {
+		out += " (" + f.Path + ")"
+	}
+	return out
+}
+
+type Report struct {
+	Solver        string
+	Cases, Passed int
+	Failures      []*Failure
+}
+
+func (r *Report) String() string {
+	out := fmt.Sprintf("%s: %d/%d passed\n", r.Solver, r.Passed, r.Cases)
+	for _, f := range r.Failures {
+		out += fmt.Sprintln(f)
+	}
+	return out
+}
+
+func (r *Report) Err() error {
+	if len(r.Failures) == 0 {
+		return nil
+	}
+	return errors.New(strings.TrimSpace(r.String()))
+}
+
+type Runner struct {
+	Reference core.Solver
+	Dir       string  // failed instances, not saved if empty
+	Tolerance float64 // relative cost difference
+}
+
+func NewRunner(reference core.Solver, dir string) *Runner {
+	return &Runner{reference, dir, 1e-6}
+}
+
+func (r *Runner) Run(name string, solver core.Solver, cases []*Case) *Report {
+	report := &Report{Solver: name, Cases: len(cases), Failures: make([]*Failure, 0)}
+	for _, c := range cases {
+		if msg := r.check(solver, c.Graph); msg != "" {
+			f := &Failure{Case: c.Name, Message: msg}
+			if r.Dir != "" {
+				path, err := r.save(name, c)
+				if err != nil {
+					f.Message += fmt.Sprint(", Error saving instance: ", err)
+				}
+				f.Path = path
+			}
+			report.Failures = append(report.Failures, f)
+			continue
+		}
+		report.Passed++
+	}
+	return report
+}
+
+func (r *Runner) check(solver core.Solver, g *fct.Graph) string {
+	expected, err := r.Reference.ComputeFlow(g)
+	if err != nil {
+		return fmt.Sprint("Error computing reference flow: ", err)
+	}
+	if err := r.Check(solver, g, core.FlowCost(g, expected)); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// Check solves g, the flow must be feasible with linear cost optimum.
+func (r *Runner) Check(solver core.Solver, g *fct.Graph, optimum float64) error {
+	flow, err := solver.ComputeFlow(g)
+	if err != nil {
+		return fmt.Errorf("Error computing flow: %s", err)
+	}
+	if v := core.Verify(g, flow); !v.Feasible() {
+		return errors.New(strings.Join(v.Violations, ", "))
+	}
+	if cost := core.FlowCost(g, flow); math.Abs(cost-optimum) > r.Tolerance*math.Max(1., math.Abs(optimum)) {
+		return fmt.Errorf("Wrong cost %.4f, reference %.4f", cost, optimum)
+	}
+	return nil
+}
+
+func (r *Runner) save(solver string, c *Case) (string, error) {
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(r.Dir, solver+"-"+c.Name+".DAT")
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return path, fct.WriteGraph(file, c.Graph, c.Name)
+}
@@ -0,0 +1,79 @@
+package conformance
+
+import (
+	"io"
+	"os"
+	_ "parallax/bundle"
+	"parallax/core"
+	"parallax/fct"
+	"testing"
+)
+
+// Gurobi is checked when the library (and a license) is available
+func gurobiAvailable() bool {
+	s := core.NewGurobiSolver()
+	s.SetLog(io.Discard)
+	_, err := s.ComputeFlow(Generate(1, 2, 2))
+	return err == nil
+}
+
+// Generated and bundled instances (and their linear relaxation), the
+// reference is cycle canceling.
+func TestSolvers(t *testing.T) {
+	cases := Cases(1, 30)
+	bundled, err := Bundled(1)
+	if err != nil {
+		t.Fatal("Error loading bundled instances:", err)
+	}
+	for _, c := range bundled {
+		cases = append(cases, c, &Case{c.Name + "-lp", core.LinearRelaxation(c.Graph)})
+	}
+	names := []string{core.SOLVER_SSP, core.SOLVER_COST_SCALING}
+	if gurobiAvailable() {
+		names = append(names, core.SOLVER_GUROBI)
+	} else {
+		t.Log("Gurobi not available, not checked")
+	}
+	r := NewRunner(NewCycleCancelingSolver(), "")
+	for _, name := range names {
+		s := core.NewSolver(name)
+		if l, ok := s.(core.Logged); ok {
+			l.SetLog(io.Discard)
+		}
+		report := r.Run(name, s, cases)
+		if err := report.Err(); err != nil {
+			t.Error(err)
+		}
+		if report.Passed != len(cases) {
+			t.Error("Wrong number of cases passed:", report)
+		}
+	}
+}
+
+// Drops the first flow of the reference solution
+type brokenSolver struct{}
+
+func (brokenSolver) ComputeFlow(g *fct.Graph) ([]*core.EdgeFlow, error) {
+	flow, err := core.NewSSPSolver().ComputeFlow(g)
+	return flow[1:], err
+}
+
+func TestFailures(t *testing.T) {
+	dir := t.TempDir()
+	r := NewRunner(core.NewSSPSolver(), dir)
+	report := r.Run("Broken", brokenSolver{}, Cases(1, 3))
+	if report.Passed != 0 || len(report.Failures) != 3 || report.Err() == nil {
+		t.Fatal("Wrong report:", report)
+	}
+	f := report.Failures[0]
+	g, err := fct.LoadGraph(f.Path, 0)
+	if err != nil {
+		t.Fatal("Error loading failed instance:", f, err)
+	}
+	if core.Verify(g, nil).Feasible() {
+		t.Error("No violations for empty flow")
+	}
+	if _, err := os.Stat(f.Path); err != nil {
+		t.Error("Instance not saved:", f)
+	}
+}
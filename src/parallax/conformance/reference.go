@@ -0,0 +1,138 @@
+package conformance
+
+import (
+	"errors"
+	"math"
+	"parallax/core"
+	"parallax/fct"
+)
+
+// Reference solver - cycle canceling: the northwest corner flow (feasible)
+// is improved along negative cycles of the residual network, found by
+// Bellman-Ford, until there is none. Optimality does not depend on the
+// shortest paths of SSP or the prices of cost scaling. Slow, test instances
+// only.
+
+type CycleCancelingSolver struct{}
+
+func NewCycleCancelingSolver() *CycleCancelingSolver {
+	return &CycleCancelingSolver{}
+}
+
+const cycleEpsilon = 1e-9
+
+type residualArc struct {
+	from, to int
+	cost     float64
+	edge     int
+	forward  bool
+}
+
+func (*CycleCancelingSolver) ComputeFlow(g *fct.Graph) ([]*core.EdgeFlow, error) {
+	start, err := core.NewNorthwestCornerSolver().ComputeFlow(g)
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]*fct.Edge, 0, len(g.Edges))
+	index := make(map[*fct.Edge]int)
+	nodes := make(map[*fct.Vertex]int)
+	node := func(v *fct.Vertex) int {
+		if k, found := nodes[v]; found {
+			return k
+		}
+		nodes[v] = len(nodes)
+		return nodes[v]
+	}
+	for _, e := range g.Edges {
+		if g.Enabled(e) {
+			index[e] = len(edges)
+			edges = append(edges, e)
+			node(e.I)
+			node(e.J)
+		}
+	}
+	x := make([]float64, len(edges))
+	for _, f := range start {
+		k, found := index[g.Arc(f.Source, f.Sink)]
+		if !found {
+			return nil, errors.New("Initial flow on missing edge " + f.String())
+		}
+		x[k] += f.Amount
+	}
+
+	for {
+		arcs := make([]*residualArc, 0, 2*len(edges))
+		for k, e := range edges {
+			i, j, c := nodes[e.I], nodes[e.J], g.Cost(e)
+			if x[k] < g.Capacity(e)-cycleEpsilon {
+				arcs = append(arcs, &residualArc{i, j, c, k, true})
+			}
+			if x[k] > e.Data.Lower+cycleEpsilon {
+				arcs = append(arcs, &residualArc{j, i, -c, k, false})
+			}
+		}
+		cycle := negativeCycle(len(nodes), arcs)
+		if cycle == nil {
+			break
+		}
+		delta := math.Inf(1)
+		for _, a := range cycle {
+			e := edges[a.edge]
+			if a.forward {
+				delta = math.Min(delta, g.Capacity(e)-x[a.edge])
+			} else {
+				delta = math.Min(delta, x[a.edge]-e.Data.Lower)
+			}
+		}
+		for _, a := range cycle {
+			if a.forward {
+				x[a.edge] += delta
+			} else {
+				x[a.edge] -= delta
+			}
+		}
+	}
+
+	result := make([]*core.EdgeFlow, 0)
+	for k, e := range edges {
+		if x[k] > cycleEpsilon {
+			result = append(result, &core.EdgeFlow{Source: e.I.Data.Id, Sink: e.J.Data.Id, Amount: x[k]})
+		}
+	}
+	return result, nil
+}
+
+// Bellman-Ford from every node (distances 0), the arcs of a negative cycle
+// or nil.
+func negativeCycle(n int, arcs []*residualArc) []*residualArc {
+	dist := make([]float64, n)
+	pred := make([]*residualArc, n)
+	last := -1
+	for k := 0; k < n; k++ {
+		last = -1
+		for _, a := range arcs {
+			if d := dist[a.from] + a.cost; d < dist[a.to]-cycleEpsilon {
+				dist[a.to] = d
+				pred[a.to] = a
+				last = a.to
+			}
+		}
+		if last < 0 {
+			return nil
+		}
+	}
+	// n rounds back from a node still relaxed is on the cycle
+	v := last
+	for k := 0; k < n; k++ {
+		v = pred[v].from
+	}
+	cycle := make([]*residualArc, 0)
+	for u := v; ; {
+		a := pred[u]
+		cycle = append(cycle, a)
+		if u = a.from; u == v {
+			break
+		}
+	}
+	return cycle
+}
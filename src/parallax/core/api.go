@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"io"
 )
 
 type BidEngine interface {
@@ -9,6 +10,34 @@ type BidEngine interface {
 	Update(f *Flow)
 }
 
+// Engines reporting metrics (solver time)
+type Instrumented interface {
+	Instrument(m *PlayerMetrics)
+}
+
+// Engines whose solver results come from a shared cache
+type Cached interface {
+	SetResultCache(c *ResultCache)
+}
+
+// Engines solving unbalanced instances with a dummy source or sink (see
+// BalancedSolver)
+type Balanced interface {
+	SetBalance(penalty float64)
+}
+
+// Engines and solvers writing to a handler log
+type Logged interface {
+	SetLog(w io.Writer)
+}
+
+// Decorators pass the log to the solver they wrap.
+func setLog(s Solver, w io.Writer) {
+	if l, ok := s.(Logged); ok {
+		l.SetLog(w)
+	}
+}
+
 // Game Protocol - API
 
 type Match struct {
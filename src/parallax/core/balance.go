@@ -0,0 +1,42 @@
+package core
+
+import (
+	"io"
+	"parallax/fct"
+)
+
+// Balanced solver - unbalanced instances are solved with a dummy source or
+// sink (penalty cost per unit), flows to and from the dummy are dropped.
+// Enabled by -balance in tool/gurobi, tool/engine and tool/player (engines
+// implementing Balanced).
+
+type BalancedSolver struct {
+	solver  Solver
+	penalty float64
+}
+
+func NewBalancedSolver(solver Solver, penalty float64) *BalancedSolver {
+	return &BalancedSolver{solver, penalty}
+}
+
+func (s *BalancedSolver) SetLog(w io.Writer) {
+	setLog(s.solver, w)
+}
+
+func (s *BalancedSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
+	b, dummy := g.Balance(s.penalty)
+	flow, err := s.solver.ComputeFlow(b)
+	if err != nil || dummy == nil {
+		return flow, err
+	}
+	id := dummy.Data.Id
+	source := b.Sources[id] == dummy
+	result := make([]*EdgeFlow, 0, len(flow))
+	for _, f := range flow {
+		if source && f.Source == id || !source && f.Sink == id {
+			continue
+		}
+		result = append(result, f)
+	}
+	return result, nil
+}
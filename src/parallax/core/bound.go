@@ -0,0 +1,219 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"parallax/fct"
+	"parallax/gurobi"
+	"time"
+)
+
+// Lower bounds for the FCTP optimum - heuristics and engine decisions are
+// judged by the gap to the bound.
+
+const (
+	BOUND_LP         string = "LP"
+	BOUND_STRONG_LP         = "StrongLP"
+	BOUND_LAGRANGIAN        = "Lagrangian"
+)
+
+type Bound struct {
+	Method                string
+	Value, Incumbent, Gap float64 // Gap relative to the incumbent
+	Iterations            int
+	Trace                 []float64 // Lagrangian value by iteration
+	Steps                 []float64 // step size factor (lambda) by iteration
+	Time                  time.Duration
+}
+
+func (b *Bound) String() string {
+	return fmt.Sprintf("%s: bound %.2f, incumbent %.2f, gap %.2f%%, iterations %d, %s", b.Method, b.Value, b.Incumbent, 100*b.Gap, b.Iterations, b.Time)
+}
+
+func newBound(method string, value, incumbent float64, start time.Time) *Bound {
+	b := &Bound{method, value, incumbent, 0., 0, make([]float64, 0), make([]float64, 0), time.Since(start)}
+	if incumbent != 0 {
+		b.Gap = (incumbent - value) / math.Abs(incumbent)
+	}
+	return b
+}
+
+// Incumbent is the FCTP objective of the linear relaxation flow (SSP), used
+// when no incumbent is supplied.
+func Incumbent(g *fct.Graph) (float64, error) {
+	flow, err := NewSSPSolver().ComputeFlow(LinearRelaxation(g))
+	if err != nil {
+		return 0, err
+	}
+	return Objective(g, flow), nil
+}
+
+// LPBound is the linear relaxation optimum (fixed costs spread over the
+// capacity) computed by solver.
+func LPBound(g *fct.Graph, solver Solver, incumbent float64) (*Bound, error) {
+	start := time.Now()
+	lp := LinearRelaxation(g)
+	flow, err := solver.ComputeFlow(lp)
+	if err != nil {
+		return nil, err
+	}
+	return newBound(BOUND_LP, FlowCost(lp, flow), incumbent, start), nil
+}
+
+// StrongLPBound is the linear relaxation with variable upper bound
+// constraints (Gurobi):
+//
+// minimize v(i,j) * x(i,j) + f(i,j) * y(i,j)
+// l(i,j) <= x(i,j) <= min{si,sj,u(i,j)} * y(i,j), 0 <= y(i,j) <= 1
+// (y(i,j) = 1 when l(i,j) > 0, the arc is always used)
+// each i sum(i) x(i,j) = si
+// each j sum(j) x(i,j) = sj
+func StrongLPBound(g *fct.Graph, incumbent float64) (*Bound, error) {
+	start := time.Now()
+	if err := g.Diagnose(); err != nil {
+		return nil, err
+	}
+	env, err := grb.NewEnv("gurobi_bound.log")
+	if err != nil {
+		return nil, err
+	}
+	defer env.Dispose()
+	model, err := grb.NewModel(env, "StrongLP")
+	if err != nil {
+		return nil, err
+	}
+	defer model.Dispose()
+
+	x := make(map[*fct.Edge]*grb.Var)
+	y := make(map[*fct.Edge]*grb.Var)
+	for _, e := range g.Edges {
+		if !g.Enabled(e) {
+			continue
+		}
+		name, obj, lower, upper := edge(g, e)
+		used := 0.
+		if lower > 0 {
+			used = 1.
+		}
+		x[e] = model.AddContVar(name, obj, lower, upper)
+		y[e] = model.AddContVar("y "+name, e.Data.FCost, used, 1.)
+	}
+	model.SetMinimize()
+	model.Update()
+
+	for e, xe := range x {
+		name, _, _, upper := edge(g, e)
+		model.AddConstr("VUB "+name, grb.ConstrExpr{xe: 1., y[e]: -upper}, grb.LESS_EQUAL, 0.)
+	}
+	expr := func(_edges []*fct.Edge) grb.ConstrExpr {
+		expr := make(grb.ConstrExpr)
+		for _, e := range _edges {
+			if evar, found := x[e]; found {
+				expr[evar] = 1.
+			}
+		}
+		return expr
+	}
+	for _, v := range g.Sources {
+		name, size := vertex(v)
+		model.AddConstr(name, expr(v.EdgeOut), grb.EQUAL, size)
+	}
+	for _, v := range g.Sinks {
+		name, size := vertex(v)
+		model.AddConstr(name, expr(v.EdgeIn), grb.EQUAL, size)
+	}
+
+	model.Optimize()
+	opt, err := model.Optimal()
+	if err != nil {
+		return nil, err
+	}
+	if !opt {
+		return nil, errors.New("Model is not optimal!")
+	}
+	obj, err := model.ObjectiveValue()
+	if err != nil {
+		return nil, err
+	}
+	return newBound(BOUND_STRONG_LP, obj, incumbent, start), nil
+}
+
+// Lagrangian relaxation of the demand constraints, multipliers u(j) by
+// subgradient optimization (step size Polyak, halved after 20 iterations
+// without improvement). Each source is then a fixed charge knapsack:
+//
+// minimize (v(i,j) + u(j)) * x(i,j) + f(i,j) * y(i,j) - sum(j) u(j) * sj
+// 0 <= x(i,j) <= min{si,sj,u(i,j)} * y(i,j), y(i,j) in {0, 1}, sum(i) x(i,j) = si
+//
+// solved by branch and bound (lower bounds are left out, a relaxation). The
+// bound is the best Lagrangian value, at least the LP bound when converged.
+func LagrangianBound(g *fct.Graph, incumbent float64, iterations int) (*Bound, error) {
+	start := time.Now()
+	if err := g.Diagnose(); err != nil {
+		return nil, err
+	}
+	if incumbent <= 0 {
+		var err error
+		if incumbent, err = Incumbent(g); err != nil {
+			return nil, err
+		}
+	}
+	sources := sortedIds(g.Sources)
+	sinks := sortedIds(g.Sinks)
+	u := make(map[int]float64, len(sinks))
+	best := math.Inf(-1)
+	trace := make([]float64, 0, iterations)
+	steps := make([]float64, 0, iterations)
+	lambda, stall := 2., 0
+	k := 0
+	for k < iterations && lambda > 1e-4 {
+		k++
+		value := 0.
+		for _, id := range sinks {
+			value -= u[id] * g.Sinks[id].Data.Size
+		}
+		shipped := make(map[int]float64, len(sinks))
+		for _, id := range sources {
+			items := make([]*knapsackItem, 0)
+			for _, e := range g.Sources[id].EdgeOut {
+				if g.Enabled(e) {
+					items = append(items, &knapsackItem{e.J.Data.Id, g.Cost(e) + u[e.J.Data.Id], e.Data.FCost, g.Capacity(e)})
+				}
+			}
+			v, x := fixedChargeKnapsack(items, g.Sources[id].Data.Size, 1000)
+			if math.IsInf(v, 1) {
+				return nil, fmt.Errorf("Source %d cannot ship its supply", id)
+			}
+			value += v
+			for i, item := range items {
+				shipped[item.id] += x[i]
+			}
+		}
+		trace = append(trace, value)
+		if value > best+1e-9 {
+			stall = 0
+		} else if stall++; stall >= 20 {
+			lambda, stall = lambda/2., 0
+		}
+		best = math.Max(best, value)
+		steps = append(steps, lambda)
+		norm := 0.
+		for _, id := range sinks {
+			d := shipped[id] - g.Sinks[id].Data.Size
+			norm += d * d
+		}
+		if norm < 1e-9 || incumbent-value < 1e-6*math.Max(1., math.Abs(incumbent)) {
+			break
+		}
+		step := lambda * (incumbent - value) / norm
+		for _, id := range sinks {
+			u[id] += step * (shipped[id] - g.Sinks[id].Data.Size)
+		}
+	}
+	b := newBound(BOUND_LAGRANGIAN, best, incumbent, start)
+	b.Iterations = k
+	b.Trace = trace
+	b.Steps = steps
+	return b, nil
+}
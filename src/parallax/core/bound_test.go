@@ -0,0 +1,91 @@
+package core_test
+
+import (
+	"math"
+	"parallax/conformance"
+	"parallax/core"
+	"parallax/fct"
+	"testing"
+)
+
+func TestLagrangianBound(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		g := conformance.Generate(seed, 3, 3)
+		optimum := conformance.Optimum(g)
+		lp, err := core.LPBound(g, core.NewSSPSolver(), optimum)
+		if err != nil {
+			t.Fatal("Error computing LP bound:", err)
+		}
+		b, err := core.LagrangianBound(g, optimum, 200)
+		if err != nil {
+			t.Fatal("Error computing Lagrangian bound:", err)
+		}
+		if b.Value > optimum+1e-6 || b.Value < lp.Value-1e-6 {
+			t.Error("Wrong bound:", b, lp)
+		}
+		if b.Iterations != len(b.Trace) || b.Gap < -1e-9 || b.Gap != (optimum-b.Value)/optimum {
+			t.Error("Wrong trace or gap:", b)
+		}
+		for _, v := range b.Trace {
+			if v > b.Value {
+				t.Error("Trace above bound:", v, b)
+			}
+		}
+	}
+}
+
+func TestLagrangianIncumbent(t *testing.T) {
+	g, err := fct.LoadGraph("../bundle/data/N104.DAT", 0)
+	if err != nil {
+		t.Fatal("Error loading N104:", err)
+	}
+	incumbent, err := core.Incumbent(g)
+	if err != nil {
+		t.Fatal("Error computing incumbent:", err)
+	}
+	b, err := core.LagrangianBound(g, 0, 300)
+	if err != nil {
+		t.Fatal("Error computing Lagrangian bound:", err)
+	}
+	lp, _ := core.LPBound(g, core.NewSSPSolver(), incumbent)
+	if b.Incumbent != incumbent || b.Value > incumbent || b.Value < lp.Value-1e-6 {
+		t.Error("Wrong bound:", b, lp)
+	}
+}
+
+// Lambda is halved after 20 iterations without improvement only
+func TestLagrangianSteps(t *testing.T) {
+	g, err := fct.LoadGraph("../bundle/data/N104.DAT", 0)
+	if err != nil {
+		t.Fatal("Error loading N104:", err)
+	}
+	b, err := core.LagrangianBound(g, 0, 300)
+	if err != nil {
+		t.Fatal("Error computing Lagrangian bound:", err)
+	}
+	if len(b.Steps) != b.Iterations || b.Steps[0] != 2. {
+		t.Fatal("Wrong steps:", b.Steps)
+	}
+	best, stall, halved := math.Inf(-1), 0, 0
+	for k, v := range b.Trace {
+		if v > best+1e-9 {
+			stall = 0
+		} else {
+			stall++
+		}
+		best = math.Max(best, v)
+		expected := 20
+		if k > 0 && b.Steps[k] != b.Steps[k-1] {
+			halved++
+			if stall != expected || b.Steps[k] != b.Steps[k-1]/2. {
+				t.Fatal("Lambda halved after", stall, "iterations without improvement:", k, b.Steps[k-1], b.Steps[k])
+			}
+			stall = 0
+		} else if stall >= expected {
+			t.Fatal("Lambda not halved after", stall, "iterations without improvement:", k)
+		}
+	}
+	if halved == 0 || b.Steps[len(b.Steps)-1] >= 2. {
+		t.Error("Lambda never halved:", b)
+	}
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"math"
+	"parallax/fct"
+	"sort"
+)
+
+// Initial solutions - the classic transportation constructors, fast
+// approximate solvers and starting flows for the heuristics. Each ships the
+// most it can (supply, demand and capacity left) on arcs in turn: by
+// source and sink id (northwest corner), by cost (least cost), by the
+// largest regret between the two cheapest arcs of a source or sink (Vogel)
+// or by cost plus the fixed cost spread over the capacity (fixed charge,
+// v + f / min{si,sj,u}). Arcs are not always enough on incomplete instances,
+// the supply left is shipped along shortest paths (as SSP).
+
+const (
+	ruleNorthwestCorner = iota
+	ruleLeastCost
+	ruleVogel
+	ruleFixedCharge
+)
+
+type ConstructiveSolver struct {
+	rule int
+}
+
+func NewNorthwestCornerSolver() *ConstructiveSolver {
+	return &ConstructiveSolver{ruleNorthwestCorner}
+}
+
+func NewLeastCostSolver() *ConstructiveSolver {
+	return &ConstructiveSolver{ruleLeastCost}
+}
+
+func NewVogelSolver() *ConstructiveSolver {
+	return &ConstructiveSolver{ruleVogel}
+}
+
+func NewFixedChargeSolver() *ConstructiveSolver {
+	return &ConstructiveSolver{ruleFixedCharge}
+}
+
+// Infeasible instances return *fct.Infeasible before solving.
+func (s *ConstructiveSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
+	if err := g.Diagnose(); err != nil {
+		return nil, err
+	}
+	n := newNetwork(g)
+	switch s.rule {
+	case ruleNorthwestCorner:
+		n.greedy(n.arcOrder(func(k int) (float64, float64) {
+			return float64(n.edges[k].I.Data.Id), float64(n.edges[k].J.Data.Id)
+		}))
+	case ruleLeastCost:
+		n.greedy(n.arcOrder(func(k int) (float64, float64) {
+			return n.cost[2*k], 0.
+		}))
+	case ruleVogel:
+		n.vogel()
+	case ruleFixedCharge:
+		n.greedy(n.arcOrder(func(k int) (float64, float64) {
+			e := n.edges[k]
+			c := g.Capacity(e)
+			if c <= 0 {
+				return math.Inf(1), 0.
+			}
+			return n.cost[2*k] + e.Data.FCost/c, 0.
+		}))
+	}
+	if err := n.augment(); err != nil {
+		return nil, err
+	}
+	return n.flow(), nil
+}
+
+// Edges sorted by key (ties by edge order)
+func (n *network) arcOrder(key func(k int) (float64, float64)) []int {
+	order := make([]int, len(n.edges))
+	first := make([]float64, len(n.edges))
+	second := make([]float64, len(n.edges))
+	for k := range order {
+		order[k] = k
+		first[k], second[k] = key(k)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		return first[i] < first[j] || first[i] == first[j] && second[i] < second[j]
+	})
+	return order
+}
+
+func (n *network) greedy(order []int) {
+	for _, k := range order {
+		n.ship(k)
+	}
+}
+
+// Ships the most edge k can carry, the amount shipped.
+func (n *network) ship(k int) float64 {
+	i, j := n.head[2*k+1], n.head[2*k]
+	amount := math.Min(math.Min(n.supply[i], -n.supply[j]), n.cap[2*k])
+	if amount <= flowEpsilon {
+		return 0.
+	}
+	n.push(2*k, amount)
+	n.supply[i] -= amount
+	n.supply[j] += amount
+	return amount
+}
+
+func (n *network) available(k int) bool {
+	return n.cap[2*k] > flowEpsilon && n.supply[n.head[2*k+1]] > flowEpsilon && n.supply[n.head[2*k]] < -flowEpsilon
+}
+
+// Vogel's approximation - the source or sink with the largest difference
+// between its two cheapest available arcs (a single arc first) ships on the
+// cheapest one.
+func (n *network) vogel() {
+	for {
+		best, arc := math.Inf(-1), -1
+		for _, arcs := range n.adj {
+			c1, c2, cheapest := math.Inf(1), math.Inf(1), -1
+			for _, a := range arcs {
+				k := a / 2
+				if !n.available(k) {
+					continue
+				}
+				if c := n.cost[2*k]; c < c1 {
+					c1, c2, cheapest = c, c1, k
+				} else if c < c2 {
+					c2 = c
+				}
+			}
+			if cheapest < 0 {
+				continue
+			}
+			if regret := c2 - c1; regret > best {
+				best, arc = regret, cheapest
+			}
+		}
+		if arc < 0 || n.ship(arc) <= 0 {
+			return
+		}
+	}
+}
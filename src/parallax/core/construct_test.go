@@ -0,0 +1,110 @@
+package core_test
+
+import (
+	"parallax/conformance"
+	"parallax/core"
+	"parallax/fct"
+	"testing"
+)
+
+var testConstructors = map[string]core.Solver{
+	core.SOLVER_NORTHWEST_CORNER: core.NewNorthwestCornerSolver(),
+	core.SOLVER_LEAST_COST:       core.NewLeastCostSolver(),
+	core.SOLVER_VOGEL:            core.NewVogelSolver(),
+	core.SOLVER_FIXED_CHARGE:     core.NewFixedChargeSolver(),
+}
+
+// Supplies 20 30 25, demands 10 10 35 20 (costs computed by hand)
+func constructGraph() *fct.Graph {
+	g := fct.NewGraph()
+	g.SourceSize(1, 20.)
+	g.SourceSize(2, 30.)
+	g.SourceSize(3, 25.)
+	g.SinkSize(4, 10.)
+	g.SinkSize(5, 10.)
+	g.SinkSize(6, 35.)
+	g.SinkSize(7, 20.)
+	costs := [][]float64{{20, 10, 10, 30}, {30, 40, 10, 20}, {10, 20, 30, 40}}
+	for i, row := range costs {
+		for j, c := range row {
+			g.NewEdge(i+1, j+4, c, 0.)
+		}
+	}
+	return g
+}
+
+func checkFlow(t *testing.T, name string, g *fct.Graph, flow []*core.EdgeFlow) {
+	if v := core.Verify(g, flow); !v.Feasible() {
+		t.Error(name, v)
+	}
+}
+
+func TestConstructors(t *testing.T) {
+	g := constructGraph()
+	expected := map[string]float64{
+		core.SOLVER_NORTHWEST_CORNER: 1550.,
+		core.SOLVER_LEAST_COST:       1250.,
+		core.SOLVER_VOGEL:            1300.,
+		core.SOLVER_FIXED_CHARGE:     1250., // no fixed costs, as least cost
+	}
+	for name, s := range testConstructors {
+		flow, err := s.ComputeFlow(g)
+		if err != nil {
+			t.Fatal(name, "error computing flow:", err)
+		}
+		checkFlow(t, name, g, flow)
+		if v := core.FlowCost(g, flow); v != expected[name] {
+			t.Error(name, "wrong cost:", v, "expected", expected[name])
+		}
+	}
+}
+
+func TestConstructorsFixedCharge(t *testing.T) {
+	g := constructGraph()
+	// cheap arc 1->5 made expensive by its fixed cost
+	g.Arc(1, 5).Data.FCost = 1000.
+	lc, _ := core.NewLeastCostSolver().ComputeFlow(g)
+	fc, _ := core.NewFixedChargeSolver().ComputeFlow(g)
+	if core.Objective(g, fc) >= core.Objective(g, lc) {
+		t.Error("Fixed charge not better than least cost:", core.Objective(g, fc), core.Objective(g, lc))
+	}
+}
+
+// Incomplete and bounded instances, supply left by the greedy is repaired
+func TestConstructorsFeasible(t *testing.T) {
+	instances := []*fct.Graph{}
+	for seed := int64(1); seed <= 20; seed++ {
+		g := conformance.Generate(seed, 2+int(seed)%7, 2+int(seed*3)%11)
+		o := g.Overlay()
+		for i, e := range g.Edges {
+			if i%3 == 1 {
+				o.Disable(e.I.Data.Id, e.J.Data.Id)
+			}
+		}
+		if o.Diagnose() == nil {
+			instances = append(instances, o)
+		}
+		instances = append(instances, g)
+	}
+	g := constructGraph()
+	g.SetBounds(1, 4, 5., 10.)
+	g.SetBounds(3, 4, 0., 5.)
+	instances = append(instances, g)
+	for _, g := range instances {
+		optimum, err := core.NewSSPSolver().ComputeFlow(g)
+		if err != nil {
+			t.Fatal("Error computing reference flow:", err)
+		}
+		for name, s := range testConstructors {
+			flow, err := s.ComputeFlow(g)
+			if err != nil {
+				t.Error(name, "error computing flow:", g, err)
+				continue
+			}
+			checkFlow(t, name, g, flow)
+			if core.FlowCost(g, flow) < core.FlowCost(g, optimum)-1e-6 {
+				t.Error(name, "cost below optimum:", core.FlowCost(g, flow), core.FlowCost(g, optimum))
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"math"
+	"parallax/fct"
+)
+
+// Cost scaling push-relabel (Goldberg-Tarjan) - eps-optimal flows for
+// decreasing eps, exact for integer costs scaled by the number of nodes + 1.
+// Costs are rounded to COST_SCALING_UNIT before scaling.
+
+const COST_SCALING_UNIT = 1e-4
+
+// Each refine divides eps by alpha
+const costScalingAlpha = 8
+
+type CostScalingSolver struct {
+}
+
+func NewCostScalingSolver() *CostScalingSolver {
+	return &CostScalingSolver{}
+}
+
+// Infeasible instances return *fct.Infeasible before solving.
+func (*CostScalingSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
+	if err := g.Diagnose(); err != nil {
+		return nil, err
+	}
+	n := newNetwork(g)
+	// 1-optimal for costs scaled by n + 1 is optimal
+	scale := int64(len(n.adj) + 1)
+	cost := make([]int64, len(n.cost))
+	max := int64(1)
+	for a, c := range n.cost {
+		cost[a] = int64(math.Round(c/COST_SCALING_UNIT)) * scale
+		if cost[a] > max {
+			max = cost[a]
+		}
+	}
+
+	excess := append([]float64(nil), n.supply...)
+	price := make([]int64, len(n.adj))
+	next := make([]int, len(n.adj))
+	reduced := func(u, a int) int64 {
+		return cost[a] + price[u] - price[n.head[a]]
+	}
+
+	for eps := max; ; {
+		eps /= costScalingAlpha
+		if eps < 1 {
+			eps = 1
+		}
+
+		// refine: saturate arcs with negative reduced cost, then discharge
+		for u, arcs := range n.adj {
+			for _, a := range arcs {
+				if r := n.cap[a]; r > flowEpsilon && reduced(u, a) < 0 {
+					n.push(a, r)
+					excess[u] -= r
+					excess[n.head[a]] += r
+				}
+			}
+		}
+		active := make([]int, 0)
+		queued := make([]bool, len(n.adj))
+		for u := range n.adj {
+			next[u] = 0
+			if excess[u] > flowEpsilon {
+				active = append(active, u)
+				queued[u] = true
+			}
+		}
+		for len(active) > 0 {
+			u := active[0]
+			active = active[1:]
+			queued[u] = false
+			for excess[u] > flowEpsilon {
+				if next[u] == len(n.adj[u]) {
+					// relabel: largest price keeping all residual arcs >= -eps
+					best := int64(math.MinInt64)
+					for _, a := range n.adj[u] {
+						if n.cap[a] > flowEpsilon {
+							if p := price[n.head[a]] - cost[a] - eps; p > best {
+								best = p
+							}
+						}
+					}
+					if best == math.MinInt64 {
+						return nil, errNotShipped
+					}
+					price[u] = best
+					next[u] = 0
+					continue
+				}
+				a := n.adj[u][next[u]]
+				if n.cap[a] <= flowEpsilon || reduced(u, a) >= 0 {
+					next[u]++
+					continue
+				}
+				v := n.head[a]
+				amount := math.Min(excess[u], n.cap[a])
+				n.push(a, amount)
+				excess[u] -= amount
+				excess[v] += amount
+				if excess[v] > flowEpsilon && !queued[v] {
+					active = append(active, v)
+					queued[v] = true
+				}
+			}
+		}
+		if eps == 1 {
+			break
+		}
+	}
+	return n.flow(), nil
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"parallax/fct"
+	"sort"
+)
+
+// DOT export of solver flows and game results over the instance
+
+var dotColors = []string{"blue", "red", "darkgreen", "orange", "purple", "brown", "magenta", "cyan4"}
+
+// Edge thickness from 1 to 6, proportional to the amount.
+func dotWidth(amount, max float64) float64 {
+	if max <= 0 {
+		return 1.
+	}
+	return 1. + 5.*amount/max
+}
+
+func WriteFlowDot(w io.Writer, g *fct.Graph, name string, flow []*EdgeFlow) error {
+	max := 0.
+	for _, f := range flow {
+		if f.Amount > max {
+			max = f.Amount
+		}
+	}
+	highlight := make(map[fct.Key]*fct.DotEdge)
+	for _, f := range flow {
+		key := fct.Key{Source: f.Source, Sink: f.Sink}
+		highlight[key] = &fct.DotEdge{Width: dotWidth(f.Amount, max), Color: dotColors[0], Label: fmt.Sprintf("%.2f", f.Amount)}
+	}
+	return g.WriteDot(w, name, highlight)
+}
+
+// Streams colored by owner (edges shared by several owners in black).
+func WriteStreamDot(w io.Writer, g *fct.Graph, name string, f *Flow) error {
+	owners := make([]string, 0)
+	colors := make(map[string]string)
+	max := 0.
+	for _, s := range f.Streams {
+		if _, found := colors[s.Owner]; !found {
+			colors[s.Owner] = ""
+			owners = append(owners, s.Owner)
+		}
+		if s.Amount > max {
+			max = s.Amount
+		}
+	}
+	sort.Strings(owners)
+	for i, owner := range owners {
+		colors[owner] = dotColors[i%len(dotColors)]
+	}
+	highlight := make(map[fct.Key]*fct.DotEdge)
+	for _, s := range f.Streams {
+		key := fct.Key{Source: s.Source, Sink: s.Sink}
+		if h, found := highlight[key]; found {
+			h.Width = dotWidth(s.Amount, max) + h.Width - 1.
+			h.Color = "black"
+			h.Label += fmt.Sprintf("\n%s %.2f", s.Owner, s.Amount)
+			continue
+		}
+		highlight[key] = &fct.DotEdge{Width: dotWidth(s.Amount, max), Color: colors[s.Owner], Label: fmt.Sprintf("%s %.2f", s.Owner, s.Amount)}
+	}
+	return g.WriteDot(w, name, highlight)
+}
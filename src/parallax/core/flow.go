@@ -50,18 +50,15 @@ type EdgeBid struct {
 	count        int
 }
 
-func BidGraph(g *fct.Graph, bids map[string]*BidPack, factor float64) (*fct.Graph, map[string]*EdgeBid) {
-	result := g.Clone()
+func BidGraph(g *fct.Graph, bids map[string]*BidPack, factor float64) (*fct.Graph, map[fct.Key]*EdgeBid) {
+	result := g.Overlay()
+	result.ScaleCosts(factor)
 
-	for _, e := range result.Edges {
-		_e := e.Data.(*fct.EdgeData)
-		_e.VCost *= factor
-	}
-
-	bidMap := make(map[string]*EdgeBid)
+	bidMap := make(map[fct.Key]*EdgeBid)
 	for owner, pack := range bids {
 		for _, bid := range pack.bids {
-			e, key := result.Edge(bid.source, bid.sink)
+			key := fct.Key{Source: bid.source, Sink: bid.sink}
+			e := result.Arc(bid.source, bid.sink)
 			if e == nil {
 				continue
 			}
@@ -76,15 +73,15 @@ func BidGraph(g *fct.Graph, bids map[string]*BidPack, factor float64) (*fct.Grap
 				}
 				bidMap[key] = ebid
 			}
-			_e := e.Data.(*fct.EdgeData)
-			if bid.price < _e.VCost {
+			cost := result.Cost(e)
+			if bid.price < cost {
 				ebid.owners = []string{owner}
 				ebid.price = bid.price
-				_e.VCost = bid.price
-			} else if bid.price-_e.VCost < 0.001 {
+				result.SetCost(bid.source, bid.sink, bid.price)
+			} else if bid.price-cost < 0.001 {
 				ebid.owners = append(ebid.owners, owner)
 				ebid.price = bid.price
-			} // bid.price > _e.VCost + 0.001
+			} // bid.price > cost + 0.001
 			ebid.count++
 		}
 	}
@@ -92,11 +89,10 @@ func BidGraph(g *fct.Graph, bids map[string]*BidPack, factor float64) (*fct.Grap
 	return result, bidMap
 }
 
-func BidFlow(edges []*EdgeFlow, bids map[string]*EdgeBid) *Flow {
+func BidFlow(edges []*EdgeFlow, bids map[fct.Key]*EdgeBid) *Flow {
 	result := make([]*Stream, 0)
 	for _, e := range edges {
-		key := fct.EdgeKey(e.Source, e.Sink)
-		bid, found := bids[key]
+		bid, found := bids[fct.Key{Source: e.Source, Sink: e.Sink}]
 		if !found {
 			continue
 		}
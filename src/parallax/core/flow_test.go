@@ -0,0 +1,128 @@
+package core
+
+import (
+	"bytes"
+	"parallax/fct"
+	"strings"
+	"testing"
+)
+
+func TestBidGraph(t *testing.T) {
+	g := fct.NewGraph()
+	g.NewEdge(1, 3, 2., 5.)
+	g.NewEdge(2, 3, 3., 5.)
+	a, b := NewBidPack(1), NewBidPack(2)
+	a.Bid(1, 3, 10.)
+	b.Bid(1, 3, 10.)
+	b.Bid(2, 3, 80.)
+	_g, bidMap := BidGraph(g, map[string]*BidPack{"a": a, "b": b}, 20.)
+	if v := _g.Cost(_g.Arc(1, 3)); v != 10. {
+		t.Error("Wrong clearing price (10):", v)
+	}
+	if v := _g.Cost(_g.Arc(2, 3)); v != 60. {
+		t.Error("Wrong reserve price (60):", v)
+	}
+	if v := g.Arc(1, 3).Data.VCost; v != 2. {
+		t.Error("Instance changed (2):", v)
+	}
+	f := BidFlow([]*EdgeFlow{{1, 3, 4.}, {2, 3, 1.}}, bidMap)
+	if n := len(f.Streams); n != 2 {
+		t.Fatal("Wrong number of streams (2):", n)
+	}
+	for _, s := range f.Streams {
+		if s.Amount != 2. || s.Price != 10. || s.NumberOfBids != 2 {
+			t.Error("Wrong stream:", s)
+		}
+	}
+}
+
+func benchGraph(n int) (*fct.Graph, map[string]*BidPack, []*EdgeFlow) {
+	g := fct.NewGraph()
+	pack := NewBidPack(n * n)
+	flow := make([]*EdgeFlow, 0, n*n)
+	for i := 1; i <= n; i++ {
+		for j := n + 1; j <= 2*n; j++ {
+			g.NewEdge(i, j, float64(i+j), float64(i*j))
+			pack.Bid(i, j, float64(i+j))
+			flow = append(flow, &EdgeFlow{i, j, 1.})
+		}
+	}
+	return g, map[string]*BidPack{"parallax": pack}, flow
+}
+
+func BenchmarkBidGraph(b *testing.B) {
+	g, bids, _ := benchGraph(200)
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		BidGraph(g, bids, 20.)
+	}
+}
+
+func BenchmarkBidFlow(b *testing.B) {
+	g, bids, flow := benchGraph(200)
+	_, bidMap := BidGraph(g, bids, 20.)
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		BidFlow(flow, bidMap)
+	}
+}
+
+func TestStreamDot(t *testing.T) {
+	g := fct.NewGraph()
+	g.SourceSize(1, 4.)
+	g.SinkSize(3, 4.)
+	g.NewEdge(1, 3, 2., 5.)
+	g.NewEdge(2, 3, 3., 5.)
+	f := &Flow{[]*Stream{{1, 3, 2., "a", 10., 2}, {1, 3, 2., "b", 10., 2}}}
+	var buf bytes.Buffer
+	if err := WriteStreamDot(&buf, g, "T1", f); err != nil {
+		t.Fatal("Error writing dot:", err)
+	}
+	out := buf.String()
+	for _, s := range []string{"digraph \"T1\"", "s1 [label=\"1\\n4.00\"]", "s1 -> t3 [label=\"a 2.00\\nb 2.00\", penwidth=11.00, color=\"black\"", "s2 -> t3 [label=\"3.00, 5.00\", color=gray"} {
+		if !strings.Contains(out, s) {
+			t.Error("Missing in dot output:", s, "\n", out)
+		}
+	}
+}
+
+func TestLinearRelaxation(t *testing.T) {
+	g := fct.NewGraph()
+	g.SourceSize(1, 4.)
+	g.SinkSize(3, 2.)
+	g.NewEdge(1, 3, 2., 5.)
+	lp := LinearRelaxation(g)
+	if v := lp.Cost(lp.Arc(1, 3)); v != 4.5 {
+		t.Error("Wrong relaxed cost (4.5):", v)
+	}
+	flow := []*EdgeFlow{{1, 3, 2.}}
+	if v := FlowCost(lp, flow); v != 9. {
+		t.Error("Wrong LP cost (9):", v)
+	}
+	if v := Objective(g, flow); v != 9. {
+		t.Error("Wrong objective (9):", v)
+	}
+}
+
+// Ships each source supply on its first arc (enough for the tests)
+type testSolver struct{}
+
+func (testSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
+	result := make([]*EdgeFlow, 0)
+	for _, v := range g.Sources {
+		e := v.EdgeOut[0]
+		result = append(result, &EdgeFlow{v.Data.Id, e.J.Data.Id, v.Data.Size})
+	}
+	return result, nil
+}
+
+func TestBalancedSolver(t *testing.T) {
+	g := fct.NewGraph()
+	g.SourceSize(1, 4.)
+	g.SinkSize(2, 6.)
+	g.NewEdge(1, 2, 1., 1.)
+	flow, err := NewBalancedSolver(testSolver{}, 50.).ComputeFlow(g)
+	if err != nil || len(flow) != 1 || flow[0].Source != 1 {
+		t.Error("Wrong balanced flow:", flow, err)
+	}
+}
@@ -3,8 +3,9 @@ package core
 import (
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"parallax/fct"
-	"parallax/graph"
 	"parallax/gurobi"
 )
 
@@ -13,13 +14,23 @@ func NewGurobiFlowEngine(graph *fct.Graph) *FlowEngine {
 }
 
 func NewGurobiSolver() *GurobiSolver {
-	return &GurobiSolver{}
+	return &GurobiSolver{os.Stdout}
 }
 
 type GurobiSolver struct {
+	log io.Writer
 }
 
-func (*GurobiSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
+func (s *GurobiSolver) SetLog(w io.Writer) {
+	s.log = w
+}
+
+// Unbalanced or infeasible instances return *fct.Infeasible before solving
+// (see BalancedSolver for dummy nodes).
+func (s *GurobiSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
+	if err := g.Diagnose(); err != nil {
+		return nil, err
+	}
 	env, err := grb.NewEnv("gurobi_solver.log")
 	if err != nil {
 		return nil, err
@@ -31,25 +42,40 @@ func (*GurobiSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
 	}
 	defer model.Dispose()
 
-	// minimize n(i,j) * v(i,j)
-	// 0 <= n(i,j) <= min{si,sj}
-	// each i sum(i) n(i,j) = si
-	// each j sum(j) n(i,j) = sj
+	edges := transportModel(model, g, false)
+	model.Optimize()
+	return solution(model, edges, s.log)
+}
 
-	edges := make(map[*graph.Edge]*grb.Var)
+// minimize n(i,j) * v(i,j)
+// l(i,j) <= n(i,j) <= min{si,sj,u(i,j)}
+// each i sum(i) n(i,j) = si
+// each j sum(j) n(i,j) = sj
+//
+// Disabled edges are left out, or kept with upper bound 0 (all true).
+func transportModel(model *grb.Model, g *fct.Graph, all bool) map[*fct.Edge]*grb.Var {
+	edges := make(map[*fct.Edge]*grb.Var)
 	for _, e := range g.Edges {
-		name, obj, upper := edge(e)
-		edges[e] = model.AddContVar(name, obj, 0., upper)
+		enabled := g.Enabled(e)
+		if !enabled && !all {
+			continue
+		}
+		name, obj, lower, upper := edge(g, e)
+		if !enabled {
+			lower, upper = 0., 0.
+		}
+		edges[e] = model.AddContVar(name, obj, lower, upper)
 	}
 
 	model.SetMinimize()
 	model.Update()
 
-	expr := func(_edges []*graph.Edge) grb.ConstrExpr {
+	expr := func(_edges []*fct.Edge) grb.ConstrExpr {
 		expr := make(grb.ConstrExpr)
 		for _, e := range _edges {
-			evar := edges[e]
-			expr[evar] = 1.
+			if evar, found := edges[e]; found {
+				expr[evar] = 1.
+			}
 		}
 		return expr
 	}
@@ -66,8 +92,10 @@ func (*GurobiSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
 		model.AddConstr(name, expr, grb.EQUAL, size)
 	}
 
-	model.Optimize()
+	return edges
+}
 
+func solution(model *grb.Model, edges map[*fct.Edge]*grb.Var, log io.Writer) ([]*EdgeFlow, error) {
 	opt, err := model.Optimal()
 	if err != nil {
 		return nil, err
@@ -79,7 +107,7 @@ func (*GurobiSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
 	if err != nil {
 		return nil, err
 	}
-	fmt.Printf("Optimal Objective: %f\n", obj)
+	fmt.Fprintf(log, "Optimal Objective: %f\n", obj)
 
 	result := make([]*EdgeFlow, 0, len(edges))
 	for e, v := range edges {
@@ -95,29 +123,24 @@ func (*GurobiSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
 	return result, nil
 }
 
-func edge(e *graph.Edge) (string, float64, float64) {
-	source := e.I.Data.(*fct.VertexData)
-	sink := e.J.Data.(*fct.VertexData)
+func edge(g *fct.Graph, e *fct.Edge) (string, float64, float64, float64) {
+	source := e.I.Data
+	sink := e.J.Data
 	name := fmt.Sprint(source.Id, ":", sink.Id)
 
-	obj := e.Data.(*fct.EdgeData).VCost
-
-	upper := source.Size
-	if upper > sink.Size {
-		upper = sink.Size
-	}
+	obj := g.Cost(e)
 
-	return name, obj, upper
+	return name, obj, e.Data.Lower, g.Capacity(e)
 }
 
-func vertex(v *graph.Vertex) (string, float64) {
-	_v := v.Data.(*fct.VertexData)
+func vertex(v *fct.Vertex) (string, float64) {
+	_v := v.Data
 	return fmt.Sprint("Vertex ", _v.Id), _v.Size
 }
 
-func flow(e *graph.Edge, amount float64) *EdgeFlow {
-	source := e.I.Data.(*fct.VertexData)
-	sink := e.J.Data.(*fct.VertexData)
+func flow(e *fct.Edge, amount float64) *EdgeFlow {
+	source := e.I.Data
+	sink := e.J.Data
 	return &EdgeFlow{
 		source.Id,
 		sink.Id,
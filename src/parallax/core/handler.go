@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Game Protocol - Handler
@@ -15,16 +17,44 @@ type Handler struct {
 	name    string
 	engine  BidEngine
 	verbose int
+	metrics *PlayerMetrics
+	match   *Match
+	log     io.Writer
 }
 
 func NewHandler(name string, engine BidEngine, verbose int) *Handler {
-	return &Handler{name, engine, verbose}
+	return &Handler{name, engine, verbose, nil, nil, os.Stdout}
+}
+
+func (h *Handler) Name() string {
+	return h.name
+}
+
+func (h *Handler) SetLog(w io.Writer) {
+	h.log = w
+	if n, ok := h.engine.(Logged); ok {
+		n.SetLog(w)
+	}
+}
+
+// Game is the tag of the game log, metrics are kept by game.
+func (h *Handler) SetMetrics(m *Metrics, game string) {
+	h.metrics = m.Player(game, h.name)
+	if n, ok := h.engine.(Instrumented); ok {
+		n.Instrument(h.metrics)
+	}
+}
+
+func (h *Handler) protocolError() {
+	if h.metrics != nil {
+		h.metrics.ProtocolError()
+	}
 }
 
 func (h *Handler) Connect(server string) {
 	conn, err := net.Dial("tcp", server)
 	if err != nil {
-		fmt.Println("Error connecting", server)
+		fmt.Fprintln(h.log, "Error connecting", server)
 		return
 	}
 	defer conn.Close()
@@ -36,55 +66,67 @@ func (h *Handler) Run(conn io.ReadWriter) {
 	for {
 		m, err := master.ReadString('\n')
 		if err != nil {
-			fmt.Println("Error:", err)
+			fmt.Fprintln(h.log, "Error:", err)
 			break
 		}
 		m = strings.TrimSpace(m)
-		fmt.Println("Master>", m)
+		fmt.Fprintln(h.log, "Master>", m)
 		if m == "name" {
 			name := "name " + h.name
-			fmt.Println("Parallax>", name)
+			fmt.Fprintln(h.log, "Parallax>", name)
 			fmt.Fprint(conn, name)
 		} else if strings.HasPrefix(m, "instance") {
 			n, err := parseMatch(m)
 			if err != nil {
-				fmt.Println(err)
+				fmt.Fprintln(h.log, err)
+				h.protocolError()
 				continue
 			}
 			if h.verbose > 0 {
-				fmt.Println("Match:", n)
+				fmt.Fprintln(h.log, "Match:", n)
 			}
+			h.match = n
+			start := time.Now()
 			result := h.engine.ComputeBid(n)
-			fmt.Println("Parallax>")
+			if h.metrics != nil {
+				h.metrics.Round(n, result, time.Since(start))
+			}
+			fmt.Fprintln(h.log, "Parallax>")
 			_out := result.String()
 			if h.verbose < 2 && len(_out) > 50 {
 				_out = _out[0:50] + "..."
 			}
-			fmt.Println(_out)
+			fmt.Fprintln(h.log, _out)
 			fmt.Fprint(conn, result)
 		} else if strings.HasPrefix(m, "result") {
 			n, err := parseFlow(m, master)
 			if err != nil {
-				fmt.Println(err)
+				fmt.Fprintln(h.log, err)
+				h.protocolError()
 				continue
 			}
 			if h.verbose > 0 {
-				fmt.Println("Flow:", n)
+				fmt.Fprintln(h.log, "Flow:", n)
+			}
+			if h.metrics != nil && h.match != nil {
+				h.metrics.Result(h.match.InstanceName, n)
 			}
 			h.engine.Update(n)
 		} else if strings.HasPrefix(m, "end") {
 			n, err := parseProfits(m, master)
 			if err != nil {
-				fmt.Println(err)
+				fmt.Fprintln(h.log, err)
+				h.protocolError()
 				break
 			}
 			if h.verbose > 0 {
-				fmt.Println("Profit:", n)
+				fmt.Fprintln(h.log, "Profit:", n)
 			}
-			fmt.Println("Parallax> that's all for now!")
+			fmt.Fprintln(h.log, "Parallax> that's all for now!")
 			break
 		} else {
-			fmt.Println("Parallax> dont know what to do!")
+			fmt.Fprintln(h.log, "Parallax> dont know what to do!")
+			h.protocolError()
 		}
 	}
 }
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"math"
+	"sort"
+)
+
+type knapsackItem struct {
+	id                    int
+	cost, fixed, capacity float64
+}
+
+// Fixed charge knapsack - minimize cost * x + fixed * y, sum x = size,
+// 0 <= x <= capacity * y, y in {0, 1}. The LP relaxation (unit cost
+// cost + fixed / capacity, filled greedily) leaves at most one arc partly
+// open, branched on. The value is a lower bound: the optimum, or the LP
+// relaxation if the node limit is hit (+Inf if infeasible).
+func fixedChargeKnapsack(items []*knapsackItem, size float64, limit int) (float64, []float64) {
+	const (
+		free = iota
+		open
+		closed
+	)
+	status := make([]int, len(items))
+	order := make([]int, len(items))
+	unit := make([]float64, len(items))
+	lp := func() (float64, []float64, int) {
+		value := 0.
+		for i, item := range items {
+			order[i] = i
+			switch status[i] {
+			case open:
+				unit[i] = item.cost
+				value += item.fixed
+			case free:
+				unit[i] = item.cost + item.fixed/math.Max(item.capacity, 1e-9)
+			}
+		}
+		sort.SliceStable(order, func(a, b int) bool { return unit[order[a]] < unit[order[b]] })
+		x := make([]float64, len(items))
+		left, partial := size, -1
+		for _, i := range order {
+			if left <= 1e-9 {
+				break
+			}
+			if status[i] == closed {
+				continue
+			}
+			x[i] = math.Min(left, items[i].capacity)
+			left -= x[i]
+			value += unit[i] * x[i]
+			if status[i] == free && x[i] < items[i].capacity-1e-9 {
+				partial = i
+			}
+		}
+		if left > 1e-6 {
+			return math.Inf(1), nil, -1
+		}
+		return value, x, partial
+	}
+
+	root, rootX, _ := lp()
+	if math.IsInf(root, 1) {
+		return root, nil
+	}
+	best, bestX := math.Inf(1), rootX
+	nodes := 0
+	var search func()
+	search = func() {
+		nodes++
+		value, x, partial := lp()
+		if value >= best-1e-9 || nodes > limit {
+			return
+		}
+		if partial < 0 {
+			best, bestX = value, x
+			return
+		}
+		status[partial] = open
+		search()
+		status[partial] = closed
+		search()
+		status[partial] = free
+	}
+	search()
+	if nodes > limit || math.IsInf(best, 1) {
+		return root, rootX
+	}
+	return best, bestX
+}
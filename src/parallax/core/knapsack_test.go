@@ -0,0 +1,58 @@
+package core
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Knapsack optimum over every set of open items
+func knapsackOptimum(items []*knapsackItem, size float64) float64 {
+	best := math.Inf(1)
+	for mask := 0; mask < 1<<uint(len(items)); mask++ {
+		open := make([]*knapsackItem, 0)
+		value := 0.
+		for i, item := range items {
+			if mask&(1<<uint(i)) != 0 {
+				open = append(open, &knapsackItem{item.id, item.cost, 0., item.capacity})
+				value += item.fixed
+			}
+		}
+		if v, _ := fixedChargeKnapsack(open, size, 0); !math.IsInf(v, 1) {
+			best = math.Min(best, value+v)
+		}
+	}
+	return best
+}
+
+func TestFixedChargeKnapsack(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for k := 0; k < 50; k++ {
+		items := make([]*knapsackItem, 2+r.Intn(6))
+		for i := range items {
+			items[i] = &knapsackItem{i, float64(r.Intn(20) - 5), float64(r.Intn(100)), float64(1 + r.Intn(30))}
+		}
+		size := float64(1 + r.Intn(60))
+		value, x := fixedChargeKnapsack(items, size, 10000)
+		optimum := knapsackOptimum(items, size)
+		if math.IsInf(optimum, 1) {
+			if !math.IsInf(value, 1) {
+				t.Error("Infeasible knapsack with value:", value)
+			}
+			continue
+		}
+		if math.Abs(value-optimum) > 1e-6 {
+			t.Error("Wrong knapsack value:", value, "optimum", optimum)
+		}
+		total := 0.
+		for _, v := range x {
+			total += v
+		}
+		if math.Abs(total-size) > 1e-6 {
+			t.Error("Wrong knapsack size:", total, size)
+		}
+		if lp, _ := fixedChargeKnapsack(items, size, 0); lp > value+1e-6 {
+			t.Error("LP relaxation above optimum:", lp, value)
+		}
+	}
+}
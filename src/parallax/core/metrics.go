@@ -0,0 +1,264 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"parallax/fct"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Game Metrics - Prometheus text format
+
+type Metrics struct {
+	mu      sync.Mutex
+	graphs  fct.GraphLoader
+	results *ResultCache
+	players map[string]*PlayerMetrics
+}
+
+func NewMetrics(graphs fct.GraphLoader) *Metrics {
+	return &Metrics{
+		graphs:  graphs,
+		players: make(map[string]*PlayerMetrics),
+	}
+}
+
+type summary struct {
+	count int
+	sum   float64
+}
+
+func (s *summary) observe(d time.Duration) {
+	s.count++
+	s.sum += d.Seconds()
+}
+
+type PlayerMetrics struct {
+	m              *Metrics
+	game           string
+	name           string
+	rounds         int
+	bidLatency     summary
+	solverTime     summary
+	edgesBid       int
+	edgesWon       int
+	flow           map[string]float64
+	profit         map[string]float64
+	protocolErrors int
+}
+
+// Solver result cache reported with the game metrics.
+func (m *Metrics) SetResultCache(c *ResultCache) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = c
+}
+
+// Metrics are kept by game (tag of the game log), a player name can be in
+// several games.
+func (m *Metrics) Player(game, name string) *PlayerMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if p, found := m.players[game]; found {
+		return p
+	}
+	p := &PlayerMetrics{
+		m:      m,
+		game:   game,
+		name:   name,
+		flow:   make(map[string]float64),
+		profit: make(map[string]float64),
+	}
+	m.players[game] = p
+	return p
+}
+
+func (p *PlayerMetrics) Round(m *Match, bids *BidPack, latency time.Duration) {
+	p.m.mu.Lock()
+	defer p.m.mu.Unlock()
+	p.rounds++
+	p.bidLatency.observe(latency)
+	p.edgesBid += len(bids.bids)
+	if _, found := p.flow[m.InstanceName]; !found {
+		p.flow[m.InstanceName] = 0.
+		p.profit[m.InstanceName] = 0.
+	}
+}
+
+func (p *PlayerMetrics) Solver(d time.Duration) {
+	p.m.mu.Lock()
+	defer p.m.mu.Unlock()
+	p.solverTime.observe(d)
+}
+
+func (p *PlayerMetrics) ProtocolError() {
+	p.m.mu.Lock()
+	defer p.m.mu.Unlock()
+	p.protocolErrors++
+}
+
+// Result records the streams won by the player. Profit is the revenue
+// (amount * price) minus the instance costs (amount * VCost + FCost).
+func (p *PlayerMetrics) Result(instance string, f *Flow) {
+	var g *fct.Graph
+	if p.m.graphs != nil {
+		g = p.m.graphs.Instance(instance)
+	}
+	p.m.mu.Lock()
+	defer p.m.mu.Unlock()
+	for _, s := range f.Streams {
+		if s.Owner != p.name {
+			continue
+		}
+		p.edgesWon++
+		p.flow[instance] += s.Amount
+		profit := s.Amount * s.Price
+		if g != nil {
+			if e := g.Arc(s.Source, s.Sink); e != nil {
+				_e := e.Data
+				profit -= s.Amount*_e.VCost + _e.FCost
+			}
+		}
+		p.profit[instance] += profit
+	}
+}
+
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	games := make([]string, 0, len(m.players))
+	for game := range m.players {
+		games = append(games, game)
+	}
+	sort.Strings(games)
+
+	out := &metricsWriter{w: w}
+	header := func(name, kind, help string) {
+		out.printf("# HELP %s %s\n", name, help)
+		out.printf("# TYPE %s %s\n", name, kind)
+	}
+	each := func(f func(p *PlayerMetrics, label string)) {
+		for _, game := range games {
+			p := m.players[game]
+			f(p, fmt.Sprintf("game=%q,player=%q", game, p.name))
+		}
+	}
+
+	header("parallax_rounds_total", "counter", "Rounds played.")
+	each(func(p *PlayerMetrics, l string) {
+		out.printf("parallax_rounds_total{%s} %d\n", l, p.rounds)
+	})
+	header("parallax_bid_latency_seconds", "summary", "Bid computation latency.")
+	each(func(p *PlayerMetrics, l string) {
+		out.printf("parallax_bid_latency_seconds_sum{%s} %g\n", l, p.bidLatency.sum)
+		out.printf("parallax_bid_latency_seconds_count{%s} %d\n", l, p.bidLatency.count)
+	})
+	header("parallax_solver_seconds", "summary", "Solver time.")
+	each(func(p *PlayerMetrics, l string) {
+		out.printf("parallax_solver_seconds_sum{%s} %g\n", l, p.solverTime.sum)
+		out.printf("parallax_solver_seconds_count{%s} %d\n", l, p.solverTime.count)
+	})
+	header("parallax_edges_bid_total", "counter", "Edges bid.")
+	each(func(p *PlayerMetrics, l string) {
+		out.printf("parallax_edges_bid_total{%s} %d\n", l, p.edgesBid)
+	})
+	header("parallax_edges_won_total", "counter", "Edges won.")
+	each(func(p *PlayerMetrics, l string) {
+		out.printf("parallax_edges_won_total{%s} %d\n", l, p.edgesWon)
+	})
+	header("parallax_flow_awarded_total", "counter", "Flow awarded per instance.")
+	each(func(p *PlayerMetrics, l string) {
+		for _, i := range instances(p.flow) {
+			out.printf("parallax_flow_awarded_total{%s,instance=%q} %g\n", l, i, p.flow[i])
+		}
+	})
+	header("parallax_profit", "gauge", "Realized profit per instance.")
+	each(func(p *PlayerMetrics, l string) {
+		for _, i := range instances(p.profit) {
+			out.printf("parallax_profit{%s,instance=%q} %g\n", l, i, p.profit[i])
+		}
+	})
+	header("parallax_protocol_errors_total", "counter", "Protocol errors.")
+	each(func(p *PlayerMetrics, l string) {
+		out.printf("parallax_protocol_errors_total{%s} %d\n", l, p.protocolErrors)
+	})
+
+	if c, ok := m.graphs.(interface {
+		Stats() fct.CacheStats
+	}); ok {
+		stats := c.Stats()
+		header("parallax_instance_cache_hits_total", "counter", "Instance cache hits.")
+		out.printf("parallax_instance_cache_hits_total %d\n", stats.Hits)
+		header("parallax_instance_cache_misses_total", "counter", "Instance cache misses.")
+		out.printf("parallax_instance_cache_misses_total %d\n", stats.Misses)
+		header("parallax_instance_cache_size", "gauge", "Instances in memory.")
+		out.printf("parallax_instance_cache_size %d\n", stats.Size)
+	}
+
+	if m.results != nil {
+		stats := m.results.Stats()
+		header("parallax_solver_cache_hits_total", "counter", "Solver result cache hits.")
+		out.printf("parallax_solver_cache_hits_total %d\n", stats.Hits)
+		header("parallax_solver_cache_misses_total", "counter", "Solver result cache misses.")
+		out.printf("parallax_solver_cache_misses_total %d\n", stats.Misses)
+		header("parallax_solver_cache_size", "gauge", "Solver results in memory.")
+		out.printf("parallax_solver_cache_size %d\n", stats.Size)
+	}
+
+	return out.n, out.err
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteTo(w)
+}
+
+func instances(values map[string]float64) []string {
+	result := make([]string, 0, len(values))
+	for name := range values {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+type metricsWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (w *metricsWriter) printf(format string, a ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(w.w, format, a...)
+	w.n += int64(n)
+	w.err = err
+}
+
+// Solver decorator recording solver time
+
+type TimedSolver struct {
+	solver  Solver
+	metrics *PlayerMetrics
+}
+
+func NewTimedSolver(solver Solver, metrics *PlayerMetrics) *TimedSolver {
+	return &TimedSolver{solver, metrics}
+}
+
+func (s *TimedSolver) SetLog(w io.Writer) {
+	setLog(s.solver, w)
+}
+
+func (s *TimedSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
+	start := time.Now()
+	flow, err := s.solver.ComputeFlow(g)
+	s.metrics.Solver(time.Since(start))
+	return flow, err
+}
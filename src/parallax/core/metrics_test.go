@@ -0,0 +1,82 @@
+package core
+
+import (
+	"bytes"
+	"parallax/fct"
+	"strings"
+	"testing"
+)
+
+type testEngine struct {
+	updates int
+}
+
+func (n *testEngine) ComputeBid(m *Match) *BidPack {
+	pack := NewBidPack(2)
+	pack.Bid(1, 3, 10.)
+	pack.Bid(2, 3, 12.)
+	return pack
+}
+
+func (n *testEngine) Update(f *Flow) {
+	n.updates++
+}
+
+type testConn struct {
+	in  *strings.Reader
+	out bytes.Buffer
+}
+
+func (c *testConn) Read(p []byte) (int, error)  { return c.in.Read(p) }
+func (c *testConn) Write(p []byte) (int, error) { return c.out.Write(p) }
+
+func TestMetrics(t *testing.T) {
+	g := fct.NewGraph()
+	g.NewEdge(1, 3, 2., 5.)
+	g.NewEdge(2, 3, 3., 5.)
+	graphs := fct.NewStaticLoader(map[string]*fct.Graph{"T1": g})
+
+	n := &testEngine{}
+	h := NewHandler("Parallax", n, 0)
+	metrics := NewMetrics(graphs)
+	h.SetMetrics(metrics, "1-Parallax")
+
+	conn := &testConn{in: strings.NewReader(
+		"name\n" +
+			"instance T1 2\n" +
+			"result 2\n" +
+			"1 3 Parallax 1 10.0 4.0\n" +
+			"2 3 Other 1 12.0 1.0\n" +
+			"bogus\n" +
+			"end 1\n" +
+			"Parallax 23.0\n")}
+	h.Run(conn)
+
+	if n.updates != 1 {
+		t.Error("Engine not updated (1):", n.updates)
+	}
+
+	// same player name in another game, metrics kept apart
+	h2 := NewHandler("Parallax", &testEngine{}, 0)
+	h2.SetMetrics(metrics, "2-Parallax")
+	h2.Run(&testConn{in: strings.NewReader("instance T1 2\n")})
+
+	var out bytes.Buffer
+	metrics.WriteTo(&out)
+	text := out.String()
+	for _, line := range []string{
+		`parallax_rounds_total{game="1-Parallax",player="Parallax"} 1`,
+		`parallax_edges_bid_total{game="1-Parallax",player="Parallax"} 2`,
+		`parallax_edges_won_total{game="1-Parallax",player="Parallax"} 1`,
+		`parallax_flow_awarded_total{game="1-Parallax",player="Parallax",instance="T1"} 4`,
+		`parallax_profit{game="1-Parallax",player="Parallax",instance="T1"} 27`,
+		`parallax_protocol_errors_total{game="1-Parallax",player="Parallax"} 1`,
+		`parallax_bid_latency_seconds_count{game="1-Parallax",player="Parallax"} 1`,
+		`parallax_rounds_total{game="2-Parallax",player="Parallax"} 1`,
+		`parallax_edges_won_total{game="2-Parallax",player="Parallax"} 0`,
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Error("Missing metric:", line)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"parallax/fct"
+	"sort"
+)
+
+// Transportation network for the pure-Go solvers - sources and sinks (by id)
+// are nodes, arc 2k is the enabled edge k and arc 2k+1 its reverse. Lower
+// bounds are shipped up front, node supplies (negative for demand) and arc
+// capacities are what is left.
+
+const flowEpsilon = 1e-9
+
+var errNotShipped = errors.New("Supply not shipped, instance is infeasible")
+
+type network struct {
+	edges  []*fct.Edge
+	lower  []float64
+	head   []int
+	cap    []float64
+	cost   []float64
+	adj    [][]int
+	supply []float64
+}
+
+func newNetwork(g *fct.Graph) *network {
+	n := &network{}
+	nodes := make(map[*fct.Vertex]int)
+	for _, m := range []map[int]*fct.Vertex{g.Sources, g.Sinks} {
+		ids := make([]int, 0, len(m))
+		for id := range m {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			nodes[m[id]] = n.node()
+		}
+	}
+	for _, v := range g.Sources {
+		n.supply[nodes[v]] = v.Data.Size
+	}
+	for _, v := range g.Sinks {
+		n.supply[nodes[v]] = -v.Data.Size
+	}
+	for _, e := range g.Edges {
+		if !g.Enabled(e) {
+			continue
+		}
+		i, j := nodes[e.I], nodes[e.J]
+		l := e.Data.Lower
+		n.supply[i] -= l
+		n.supply[j] += l
+		n.arc(i, j, math.Max(g.Capacity(e)-l, 0.), g.Cost(e))
+		n.edges = append(n.edges, e)
+		n.lower = append(n.lower, l)
+	}
+	return n
+}
+
+func (n *network) node() int {
+	n.adj = append(n.adj, make([]int, 0))
+	n.supply = append(n.supply, 0.)
+	return len(n.adj) - 1
+}
+
+func (n *network) arc(i, j int, capacity, cost float64) int {
+	k := len(n.head)
+	n.head = append(n.head, j, i)
+	n.cap = append(n.cap, capacity, 0.)
+	n.cost = append(n.cost, cost, -cost)
+	n.adj[i] = append(n.adj[i], k)
+	n.adj[j] = append(n.adj[j], k+1)
+	return k
+}
+
+func (n *network) push(k int, amount float64) {
+	n.cap[k] -= amount
+	n.cap[k^1] += amount
+}
+
+// Flow on the edges, lower bounds included.
+func (n *network) flow() []*EdgeFlow {
+	result := make([]*EdgeFlow, 0)
+	for k, e := range n.edges {
+		if m := n.lower[k] + n.cap[2*k+1]; m > 0.01 {
+			result = append(result, flow(e, m))
+		}
+	}
+	return result
+}
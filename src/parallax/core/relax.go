@@ -0,0 +1,50 @@
+package core
+
+import (
+	"parallax/fct"
+)
+
+// Linear relaxation - fixed costs spread over the edge capacity, the LP
+// optimum is a lower bound for the FCTP.
+
+// LinearRelaxation is an overlay with costs VCost + FCost / capacity.
+func LinearRelaxation(g *fct.Graph) *fct.Graph {
+	result := g.Overlay()
+	for _, e := range g.Edges {
+		if c := g.Capacity(e); c > 0 {
+			result.SetCost(e.I.Data.Id, e.J.Data.Id, g.Cost(e)+e.Data.FCost/c)
+		}
+	}
+	return result
+}
+
+// FlowCost is the linear cost of the flow, sum of amount * cost.
+func FlowCost(g *fct.Graph, flow []*EdgeFlow) float64 {
+	total := 0.
+	for _, f := range flow {
+		if e := g.Arc(f.Source, f.Sink); e != nil {
+			total += f.Amount * g.Cost(e)
+		}
+	}
+	return total
+}
+
+// Objective is the FCTP cost of the flow, fixed costs paid once by edges in
+// use (entries on the same edge added up).
+func Objective(g *fct.Graph, flow []*EdgeFlow) float64 {
+	total := 0.
+	amount := make(map[*fct.Edge]float64)
+	for _, f := range flow {
+		if e := g.Arc(f.Source, f.Sink); e != nil {
+			total += f.Amount * g.Cost(e)
+			amount[e] += f.Amount
+		}
+	}
+	for _, f := range flow {
+		if e := g.Arc(f.Source, f.Sink); e != nil && amount[e] > 0 {
+			total += e.Data.FCost
+			amount[e] = 0 // charged once
+		}
+	}
+	return total
+}
@@ -0,0 +1,239 @@
+package core
+
+import (
+	"errors"
+	"io"
+	"os"
+	"parallax/fct"
+	"parallax/gurobi"
+	"sync"
+)
+
+// Gurobi session - one environment and model per instance kept between
+// solves. Cost and bound changes update the model in place and Gurobi
+// re-optimizes from the last optimal basis. Supply and demand are the
+// constraint sides, a size change needs a new session.
+
+type GurobiSession struct {
+	env    *grb.Env
+	model  *grb.Model
+	base   *fct.Graph
+	edges  map[*fct.Edge]*grb.Var
+	costs  map[*fct.Edge]float64
+	bounds map[*fct.Edge][2]float64
+	sizes  map[*fct.Vertex]float64
+	solves int
+	log    io.Writer
+}
+
+// The session is built for g's base, overlays of the same base can be
+// solved with Update.
+func NewGurobiSession(g *fct.Graph) (*GurobiSession, error) {
+	env, err := grb.NewEnv("gurobi_session.log")
+	if err != nil {
+		return nil, err
+	}
+	model, err := grb.NewModel(env, "TransportModel")
+	if err != nil {
+		env.Dispose()
+		return nil, err
+	}
+	s := &GurobiSession{
+		env,
+		model,
+		g.Base(),
+		transportModel(model, g, true),
+		make(map[*fct.Edge]float64),
+		make(map[*fct.Edge][2]float64),
+		make(map[*fct.Vertex]float64),
+		0,
+		os.Stdout,
+	}
+	for _, e := range g.Edges {
+		s.costs[e] = g.Cost(e)
+		s.bounds[e] = s.bound(g, e)
+	}
+	for _, m := range []map[int]*fct.Vertex{g.Sources, g.Sinks} {
+		for _, v := range m {
+			s.sizes[v] = v.Data.Size
+		}
+	}
+	return s, nil
+}
+
+func (s *GurobiSession) SetLog(w io.Writer) {
+	s.log = w
+}
+
+func (s *GurobiSession) Dispose() {
+	if s.model == nil {
+		return
+	}
+	s.model.Dispose()
+	s.env.Dispose()
+	s.model, s.env = nil, nil
+}
+
+func (s *GurobiSession) bound(g *fct.Graph, e *fct.Edge) [2]float64 {
+	if !g.Enabled(e) {
+		return [2]float64{0., 0.}
+	}
+	_, _, lower, upper := edge(g, e)
+	return [2]float64{lower, upper}
+}
+
+// SetCost changes one objective coefficient (cost delta for the next Solve).
+func (s *GurobiSession) SetCost(source, sink int, v float64) error {
+	e := s.base.Arc(source, sink)
+	if e == nil {
+		return errors.New("Edge not found: " + fct.EdgeKey(source, sink))
+	}
+	if s.costs[e] == v {
+		return nil
+	}
+	if err := s.edges[e].SetObj(v); err != nil {
+		return err
+	}
+	s.costs[e] = v
+	return nil
+}
+
+var errSessionModel = errors.New("Instance edges or sizes changed since the session was created")
+
+// Update applies the costs, bounds and disabled edges of g (the session base
+// or an overlay of it), only changed values are sent to the model. Edges or
+// vertices added or removed and supply or demand changed after the session
+// was created are an error (a new session is needed).
+func (s *GurobiSession) Update(g *fct.Graph) (int, error) {
+	if g.Base() != s.base {
+		return 0, errors.New("Graph is not an overlay of the session instance")
+	}
+	if len(g.Edges) != len(s.edges) || g.SourceOrder()+g.SinkOrder() != len(s.sizes) {
+		return 0, errSessionModel
+	}
+	for _, m := range []map[int]*fct.Vertex{g.Sources, g.Sinks} {
+		for _, v := range m {
+			if size, found := s.sizes[v]; !found || size != v.Data.Size {
+				return 0, errSessionModel
+			}
+		}
+	}
+	changes := 0
+	for _, e := range g.Edges {
+		v, found := s.edges[e]
+		if !found {
+			return changes, errSessionModel
+		}
+		if cost := g.Cost(e); cost != s.costs[e] {
+			if err := v.SetObj(cost); err != nil {
+				return changes, err
+			}
+			s.costs[e] = cost
+			changes++
+		}
+		if b := s.bound(g, e); b != s.bounds[e] {
+			if err := v.SetBounds(b[0], b[1]); err != nil {
+				return changes, err
+			}
+			s.bounds[e] = b
+			changes++
+		}
+	}
+	return changes, nil
+}
+
+func (s *GurobiSession) Solve() ([]*EdgeFlow, error) {
+	if err := s.model.Optimize(); err != nil {
+		return nil, err
+	}
+	s.solves++
+	flow, err := solution(s.model, s.edges, s.log)
+	if err != nil {
+		return nil, err
+	}
+	result := flow[:0]
+	for _, f := range flow {
+		if s.bounds[s.base.Arc(f.Source, f.Sink)][1] > 0 {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+// Number of optimizations since the session was created.
+func (s *GurobiSession) Solves() int {
+	return s.solves
+}
+
+// Session solver - a Solver keeping one session per instance (base graph),
+// graphs solved repeatedly with a few changed costs are re-optimized. The
+// least recently used sessions are disposed above capacity. Sessions are
+// kept by the fingerprint of the base graph (instance data), the address of
+// a graph can be reused once it is freed.
+type SessionSolver struct {
+	mu       sync.Mutex
+	sessions *fct.TypedCache[*GurobiSession]
+	log      io.Writer
+}
+
+// Sessions kept by the engines (one Gurobi environment each)
+const SESSION_CAPACITY = 4
+
+// Capacity <= 0 means unbounded.
+func NewSessionSolver(capacity int) *SessionSolver {
+	sessions := fct.NewTypedCache[*GurobiSession](capacity)
+	sessions.OnEvict(func(name string, session *GurobiSession) {
+		session.Dispose()
+	})
+	return &SessionSolver{sessions: sessions, log: os.Stdout}
+}
+
+// Infeasible graphs return *fct.Infeasible before solving, as GurobiSolver.
+func (s *SessionSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
+	if err := g.Diagnose(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := g.Base().Fingerprint()
+	load := func() (*GurobiSession, error) {
+		return NewGurobiSession(g)
+	}
+	session, err := s.sessions.Get(name, nil, load)
+	if err != nil {
+		return nil, err
+	}
+	// same data in another graph (e.g. reloaded) does not share the edges
+	err = errSessionModel
+	if session.base == g.Base() {
+		_, err = session.Update(g)
+	}
+	if err == errSessionModel {
+		// edges or sizes changed, the model is rebuilt
+		s.sessions.Invalidate(name)
+		if session, err = s.sessions.Get(name, nil, load); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	session.SetLog(s.log)
+	return session.Solve()
+}
+
+func (s *SessionSolver) SetLog(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.log = w
+}
+
+func (s *SessionSolver) Stats() fct.CacheStats {
+	return s.sessions.Stats()
+}
+
+// Dispose releases the sessions, a new one is created on the next solve.
+func (s *SessionSolver) Dispose() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions.InvalidateAll()
+}
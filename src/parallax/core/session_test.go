@@ -0,0 +1,112 @@
+package core
+
+import (
+	"io"
+	"math"
+	"parallax/fct"
+	"testing"
+)
+
+func sessionGraph() *fct.Graph {
+	g := fct.NewGraph()
+	g.SourceSize(1, 5.)
+	g.SourceSize(2, 5.)
+	g.SinkSize(3, 6.)
+	g.SinkSize(4, 4.)
+	g.NewEdge(1, 3, 1., 0.)
+	g.NewEdge(1, 4, 3., 0.)
+	g.NewEdge(2, 3, 4., 0.)
+	g.NewEdge(2, 4, 2., 0.)
+	return g
+}
+
+func newTestSession(t *testing.T, g *fct.Graph) *GurobiSession {
+	s, err := NewGurobiSession(g)
+	if err != nil {
+		t.Skip("Gurobi not available:", err)
+	}
+	s.SetLog(io.Discard)
+	return s
+}
+
+func checkSessionFlow(t *testing.T, g *fct.Graph, flow []*EdgeFlow, err error) {
+	if err != nil {
+		t.Fatal("Error solving session:", err)
+	}
+	expected, _ := NewSSPSolver().ComputeFlow(g)
+	if v := Verify(g, flow); !v.Feasible() || math.Abs(v.VCost-FlowCost(g, expected)) > 1e-6 {
+		t.Error("Wrong session flow:", v, FlowCost(g, expected))
+	}
+}
+
+func TestSessionUpdate(t *testing.T) {
+	g := sessionGraph()
+	s := newTestSession(t, g)
+	defer s.Dispose()
+	flow, err := s.Solve()
+	checkSessionFlow(t, g, flow, err)
+
+	o := g.Overlay()
+	o.SetCost(1, 3, 2.)
+	o.Disable(1, 4)
+	if n, err := s.Update(o); err != nil || n != 2 {
+		t.Fatal("Wrong update (2):", n, err)
+	}
+	flow, err = s.Solve()
+	checkSessionFlow(t, o, flow, err)
+	if n, _ := s.Update(o); n != 0 || s.Solves() != 2 {
+		t.Error("Unchanged values sent to the model:", n, s.Solves())
+	}
+
+	if _, err := s.Update(sessionGraph()); err == nil {
+		t.Error("No error for another instance")
+	}
+	g.SourceSize(1, 6.)
+	g.SinkSize(3, 7.)
+	if _, err := s.Update(g); err != errSessionModel {
+		t.Error("Sizes changed without error:", err)
+	}
+	g.RemoveEdge(2, 4)
+	if _, err := s.Update(g); err != errSessionModel {
+		t.Error("Edge removed without error:", err)
+	}
+}
+
+func TestSessionSolver(t *testing.T) {
+	newTestSession(t, sessionGraph()).Dispose()
+	s := NewSessionSolver(1)
+	s.SetLog(io.Discard)
+	defer s.Dispose()
+
+	g := sessionGraph()
+	o := g.Overlay()
+	for _, cost := range []float64{1., 5., 0.} {
+		o.SetCost(1, 3, cost)
+		flow, err := s.ComputeFlow(o)
+		checkSessionFlow(t, o, flow, err)
+	}
+	if stats := s.Stats(); stats.Loads != 1 || stats.Hits != 2 {
+		t.Error("Session not reused:", stats)
+	}
+
+	// the same data in another graph gets its own model
+	h := sessionGraph()
+	flow, err := s.ComputeFlow(h)
+	checkSessionFlow(t, h, flow, err)
+	if stats := s.Stats(); stats.Loads != 2 || stats.Invalidations != 1 {
+		t.Error("Session not rebuilt for another graph:", stats)
+	}
+
+	// the least recently used session is disposed above capacity
+	var first *GurobiSession
+	s.sessions.Each(func(name string, session *GurobiSession) {
+		first = session
+	})
+	h.SinkSize(3, 7.)
+	h.SourceSize(1, 6.)
+	flow, err = s.ComputeFlow(h)
+	checkSessionFlow(t, h, flow, err)
+	if stats := s.Stats(); stats.Evictions != 1 || stats.Size != 1 || first.model != nil {
+		t.Error("Session not disposed:", stats)
+	}
+}
@@ -0,0 +1,75 @@
+package core_test
+
+import (
+	"math"
+	"parallax/conformance"
+	"parallax/core"
+	"parallax/fct"
+	"testing"
+)
+
+// Every pure-Go solver (and the conformance reference) returns a feasible
+// flow with the optimal linear cost, computed by hand. Generated and bundled
+// instances are checked in parallax/conformance.
+
+var testSolvers = map[string]core.Solver{
+	core.SOLVER_SSP:          core.NewSSPSolver(),
+	core.SOLVER_COST_SCALING: core.NewCostScalingSolver(),
+	"CycleCanceling":         conformance.NewCycleCancelingSolver(),
+}
+
+func checkOptimum(t *testing.T, g *fct.Graph, optimum float64) {
+	r := conformance.NewRunner(conformance.NewCycleCancelingSolver(), "")
+	for name, s := range testSolvers {
+		if err := r.Check(s, g, optimum); err != nil {
+			t.Error(name, err)
+		}
+	}
+}
+
+func TestSolverSmall(t *testing.T) {
+	// sink 3 from source 1 (cost 1) or 2 (cost 4), sink 4 only from 2
+	g := fct.NewGraph()
+	g.SourceSize(1, 5.)
+	g.SourceSize(2, 5.)
+	g.SinkSize(3, 6.)
+	g.SinkSize(4, 4.)
+	g.NewEdge(1, 3, 1., 0.)
+	g.NewEdge(2, 3, 4., 0.)
+	g.NewEdge(2, 4, 2., 0.)
+	checkOptimum(t, g, 5*1.+1*4.+4*2.)
+
+	// at least 2 from 2 to 3, at most 3 from 1 to 3
+	g.SetBounds(2, 3, 2., math.Inf(1))
+	g.SetBounds(1, 3, 0., 3.)
+	g.NewEdge(1, 4, 10., 0.)
+	checkOptimum(t, g, 3*1.+2*10.+3*4.+2*2.)
+
+	// overlay costs and disabled edges
+	o := g.Overlay()
+	o.SetCost(1, 4, -1.)
+	o.Disable(2, 4)
+	checkOptimum(t, o, 1*1.+4*-1.+5*4.)
+
+	for name, s := range testSolvers {
+		o.Disable(1, 4)
+		if _, err := s.ComputeFlow(o); err == nil {
+			t.Error(name, "no error for infeasible instance")
+		}
+	}
+}
+
+func benchmarkSolver(b *testing.B, s core.Solver, n int) {
+	g := conformance.Generate(int64(n), n, n)
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		if _, err := s.ComputeFlow(g); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSSP10(b *testing.B)         { benchmarkSolver(b, core.NewSSPSolver(), 10) }
+func BenchmarkSSP50(b *testing.B)         { benchmarkSolver(b, core.NewSSPSolver(), 50) }
+func BenchmarkCostScaling10(b *testing.B) { benchmarkSolver(b, core.NewCostScalingSolver(), 10) }
+func BenchmarkCostScaling50(b *testing.B) { benchmarkSolver(b, core.NewCostScalingSolver(), 50) }
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"parallax/fct"
+	"strings"
+)
+
+// Solver result cache - flows by solver name and graph fingerprint (instance,
+// costs and bounds), shared by solvers and optionally saved to disk between
+// runs.
+
+type ResultCache struct {
+	cache *fct.TypedCache[[]*EdgeFlow]
+}
+
+// Capacity <= 0 means unbounded.
+func NewResultCache(capacity int) *ResultCache {
+	return &ResultCache{fct.NewTypedCache[[]*EdgeFlow](capacity)}
+}
+
+func (c *ResultCache) Stats() fct.CacheStats {
+	return c.cache.Stats()
+}
+
+func (c *ResultCache) Len() int {
+	return c.cache.Len()
+}
+
+// Load restores results saved by Save, a missing file is not an error.
+func (c *ResultCache) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var entries []resultEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Solver == "" {
+			continue // saved without the solver, unknown origin
+		}
+		c.cache.Put(resultKey(entry.Solver, entry.Fingerprint), nil, entry.Flow)
+	}
+	return nil
+}
+
+type resultEntry struct {
+	Solver      string
+	Fingerprint string
+	Flow        []*EdgeFlow
+}
+
+func resultKey(solver, fingerprint string) string {
+	return solver + ":" + fingerprint
+}
+
+// Save writes the results, least recently used first (a temporary file is
+// renamed, the previous file is kept on error).
+func (c *ResultCache) Save(path string) error {
+	entries := make([]resultEntry, 0, c.cache.Len())
+	c.cache.Each(func(name string, flow []*EdgeFlow) {
+		solver, fingerprint, _ := strings.Cut(name, ":")
+		entries = append(entries, resultEntry{solver, fingerprint, flow})
+	})
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Solver decorator - repeat problems are answered from the cache, errors
+// are not cached. Callers get their own slice (sorting is safe). Results are
+// kept by solver name, solvers sharing a cache must have different names.
+type CachedSolver struct {
+	solver Solver
+	name   string
+	cache  *ResultCache
+}
+
+func NewCachedSolver(solver Solver, name string, cache *ResultCache) *CachedSolver {
+	return &CachedSolver{solver, name, cache}
+}
+
+func (s *CachedSolver) SetLog(w io.Writer) {
+	setLog(s.solver, w)
+}
+
+func (s *CachedSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
+	flow, err := s.cache.cache.Get(resultKey(s.name, g.Fingerprint()), nil, func() ([]*EdgeFlow, error) {
+		return s.solver.ComputeFlow(g)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]*EdgeFlow(nil), flow...), nil
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"parallax/fct"
+	"path/filepath"
+	"testing"
+)
+
+type countSolver struct {
+	solves int
+}
+
+func (s *countSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
+	s.solves++
+	return testSolver{}.ComputeFlow(g)
+}
+
+func TestCachedSolver(t *testing.T) {
+	g := fct.NewGraph()
+	g.SourceSize(1, 4.)
+	g.SinkSize(2, 4.)
+	g.NewEdge(1, 2, 1., 1.)
+	g.NewEdge(1, 3, 1., 1.)
+	cache := NewResultCache(10)
+	s := &countSolver{}
+	solver := NewCachedSolver(s, "Count", cache)
+
+	o := g.Overlay()
+	o.SetCost(1, 3, 2.)
+	for _, _g := range []*fct.Graph{g, g.Overlay(), o, o.Clone()} {
+		if flow, err := solver.ComputeFlow(_g); err != nil || len(flow) != 1 {
+			t.Fatal("Wrong flow:", flow, err)
+		}
+	}
+	if s.solves != 2 {
+		t.Error("Wrong number of solves (2):", s.solves)
+	}
+	if stats := cache.Stats(); stats.Hits != 2 || stats.Misses != 2 {
+		t.Error("Wrong cache stats:", stats)
+	}
+
+	path := filepath.Join(t.TempDir(), "results.json")
+	if err := cache.Save(path); err != nil {
+		t.Fatal("Error saving results:", err)
+	}
+	restored := NewResultCache(10)
+	if err := restored.Load(path); err != nil || restored.Len() != 2 {
+		t.Fatal("Error loading results:", restored.Len(), err)
+	}
+	s = &countSolver{}
+	flow, _ := NewCachedSolver(s, "Count", restored).ComputeFlow(o)
+	if s.solves != 0 || len(flow) != 1 || flow[0].Amount != 4. {
+		t.Error("Result not restored:", s.solves, flow)
+	}
+}
+
+// Solvers sharing a cache (e.g. games with different engines) get their own results
+func TestCachedSolverShared(t *testing.T) {
+	g := fct.NewGraph()
+	g.SourceSize(1, 4.)
+	g.SinkSize(2, 4.)
+	g.NewEdge(1, 2, 1., 1.)
+	cache := NewResultCache(10)
+	a, b := &countSolver{}, &countSolver{}
+	for k := 0; k < 2; k++ {
+		NewCachedSolver(a, SOLVER_VOGEL, cache).ComputeFlow(g)
+		NewCachedSolver(b, SOLVER_GUROBI, cache).ComputeFlow(g)
+	}
+	if a.solves != 1 || b.solves != 1 || cache.Len() != 2 {
+		t.Error("Results shared between solvers:", a.solves, b.solves, cache.Len())
+	}
+
+	path := filepath.Join(t.TempDir(), "results.json")
+	if err := cache.Save(path); err != nil {
+		t.Fatal("Error saving results:", err)
+	}
+	restored := NewResultCache(10)
+	restored.Load(path)
+	c := &countSolver{}
+	NewCachedSolver(c, SOLVER_GUROBI, restored).ComputeFlow(g)
+	NewCachedSolver(c, SOLVER_SSP, restored).ComputeFlow(g)
+	if c.solves != 1 {
+		t.Error("Restored results not kept by solver (1):", c.solves)
+	}
+}
@@ -0,0 +1,32 @@
+package core
+
+const (
+	SOLVER_GUROBI           string = "Gurobi"
+	SOLVER_SSP                     = "SSP"
+	SOLVER_COST_SCALING            = "CostScaling"
+	SOLVER_NORTHWEST_CORNER        = "NorthwestCorner"
+	SOLVER_LEAST_COST              = "LeastCost"
+	SOLVER_VOGEL                   = "Vogel"
+	SOLVER_FIXED_CHARGE            = "FixedCharge"
+)
+
+func NewSolver(name string) Solver {
+	switch name {
+	case SOLVER_GUROBI:
+		return NewGurobiSolver()
+	case SOLVER_SSP:
+		return NewSSPSolver()
+	case SOLVER_COST_SCALING:
+		return NewCostScalingSolver()
+	case SOLVER_NORTHWEST_CORNER:
+		return NewNorthwestCornerSolver()
+	case SOLVER_LEAST_COST:
+		return NewLeastCostSolver()
+	case SOLVER_VOGEL:
+		return NewVogelSolver()
+	case SOLVER_FIXED_CHARGE:
+		return NewFixedChargeSolver()
+	default:
+		return nil
+	}
+}
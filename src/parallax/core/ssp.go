@@ -0,0 +1,151 @@
+package core
+
+import (
+	"container/heap"
+	"math"
+	"parallax/fct"
+)
+
+// Successive shortest paths - augment from a super source to a super sink
+// along shortest paths, Dijkstra with node potentials (Bellman-Ford for the
+// initial potentials, costs may be negative).
+
+type SSPSolver struct {
+}
+
+func NewSSPSolver() *SSPSolver {
+	return &SSPSolver{}
+}
+
+// Infeasible instances return *fct.Infeasible before solving.
+func (*SSPSolver) ComputeFlow(g *fct.Graph) ([]*EdgeFlow, error) {
+	if err := g.Diagnose(); err != nil {
+		return nil, err
+	}
+	n := newNetwork(g)
+	if err := n.augment(); err != nil {
+		return nil, err
+	}
+	return n.flow(), nil
+}
+
+// Ships the node supplies left from a super source to a super sink along
+// shortest paths. Starting from a flow that is not optimal (reduced costs
+// may be negative), the paths are not the shortest but the flow is feasible.
+func (n *network) augment() error {
+	s, t := n.node(), n.node()
+	required := 0.
+	for v, b := range n.supply[:s] {
+		if b > flowEpsilon {
+			n.arc(s, v, b, 0.)
+			required += b
+		} else if b < -flowEpsilon {
+			n.arc(v, t, -b, 0.)
+		}
+	}
+
+	pi := n.bellmanFord(s)
+	dist := make([]float64, len(n.adj))
+	pred := make([]int, len(n.adj))
+	shipped := 0.
+	for shipped < required-flowEpsilon {
+		n.dijkstra(s, pi, dist, pred)
+		if math.IsInf(dist[t], 1) {
+			return errNotShipped
+		}
+		for v := range pi {
+			pi[v] += math.Min(dist[v], dist[t])
+		}
+		amount := required - shipped
+		for v := t; v != s; v = n.head[pred[v]^1] {
+			amount = math.Min(amount, n.cap[pred[v]])
+		}
+		for v := t; v != s; v = n.head[pred[v]^1] {
+			n.push(pred[v], amount)
+		}
+		shipped += amount
+	}
+	return nil
+}
+
+// Distances from s over the residual arcs, unreached nodes at 0.
+func (n *network) bellmanFord(s int) []float64 {
+	dist := make([]float64, len(n.adj))
+	for v := range dist {
+		dist[v] = math.Inf(1)
+	}
+	dist[s] = 0.
+	for k := 0; k < len(n.adj); k++ {
+		changed := false
+		for u, arcs := range n.adj {
+			if math.IsInf(dist[u], 1) {
+				continue
+			}
+			for _, a := range arcs {
+				if v := n.head[a]; n.cap[a] > flowEpsilon && dist[u]+n.cost[a] < dist[v]-flowEpsilon {
+					dist[v] = dist[u] + n.cost[a]
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	for v := range dist {
+		if math.IsInf(dist[v], 1) {
+			dist[v] = 0.
+		}
+	}
+	return dist
+}
+
+// Reduced costs cost + pi[u] - pi[v] are non-negative (up to rounding).
+func (n *network) dijkstra(s int, pi, dist []float64, pred []int) {
+	done := make([]bool, len(n.adj))
+	for v := range dist {
+		dist[v] = math.Inf(1)
+		pred[v] = -1
+	}
+	dist[s] = 0.
+	q := &nodeQueue{{s, 0.}}
+	for q.Len() > 0 {
+		item := heap.Pop(q).(nodeItem)
+		u := item.node
+		if done[u] {
+			continue
+		}
+		done[u] = true
+		for _, a := range n.adj[u] {
+			v := n.head[a]
+			if n.cap[a] <= flowEpsilon || done[v] {
+				continue
+			}
+			d := dist[u] + math.Max(n.cost[a]+pi[u]-pi[v], 0.)
+			if d < dist[v] {
+				dist[v] = d
+				pred[v] = a
+				heap.Push(q, nodeItem{v, d})
+			}
+		}
+	}
+}
+
+type nodeItem struct {
+	node int
+	dist float64
+}
+
+type nodeQueue []nodeItem
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(nodeItem)) }
+
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Game Supervisor - concurrent handlers
+
+type Game struct {
+	Server  string
+	Handler *Handler
+	Log     io.Writer
+}
+
+func (g *Game) String() string {
+	return fmt.Sprint(g.Handler.Name(), "@", g.Server)
+}
+
+type Supervisor struct {
+	games []*Game
+}
+
+func NewSupervisor() *Supervisor {
+	return &Supervisor{make([]*Game, 0)}
+}
+
+// Each game must have its own engine instance, the graph loader can be shared.
+func (s *Supervisor) Add(server string, h *Handler, log io.Writer) *Game {
+	if log != nil {
+		h.SetLog(log)
+	}
+	g := &Game{server, h, log}
+	s.games = append(s.games, g)
+	return g
+}
+
+func (s *Supervisor) Games() []*Game {
+	return s.games
+}
+
+func (s *Supervisor) Run() {
+	var wg sync.WaitGroup
+	for _, g := range s.games {
+		wg.Add(1)
+		go func(g *Game) {
+			defer wg.Done()
+			g.Handler.Connect(g.Server)
+		}(g)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type safeBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *safeBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func testServer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Error listening:", err)
+	}
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("name\n"))
+		conn.Read(make([]byte, 64))
+		conn.Write([]byte("end 0\n"))
+	}()
+	return l.Addr().String()
+}
+
+func TestSupervisor(t *testing.T) {
+	s := NewSupervisor()
+	logs := []*safeBuffer{&safeBuffer{}, &safeBuffer{}}
+	s.Add(testServer(t), NewHandler("A", &testEngine{}, 0), logs[0])
+	s.Add(testServer(t), NewHandler("B", &testEngine{}, 0), logs[1])
+	s.Run()
+
+	for i, name := range []string{"A", "B"} {
+		out := logs[i].String()
+		if !strings.Contains(out, "Parallax> name "+name) {
+			t.Error("Missing game log:", name, out)
+		}
+		if !strings.Contains(out, "that's all for now!") {
+			t.Error("Game not finished:", name, out)
+		}
+	}
+}
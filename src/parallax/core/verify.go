@@ -0,0 +1,170 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"parallax/fct"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Verification of a flow not computed by a solver (bid scenarios, heuristics,
+// recorded results): constraints and FCTP cost.
+
+type Verification struct {
+	VCost, FCost, Total float64
+	Arcs                int
+	Violations          []string
+}
+
+func (v *Verification) Feasible() bool {
+	return len(v.Violations) == 0
+}
+
+func (v *Verification) String() string {
+	out := fmt.Sprintf("Arcs %d, Variable Cost %.2f, Fixed Cost %.2f, Total %.2f, Feasible %t\n", v.Arcs, v.VCost, v.FCost, v.Total, v.Feasible())
+	for _, s := range v.Violations {
+		out += fmt.Sprintln("Violation:", s)
+	}
+	return out
+}
+
+// Verify checks non-negativity, arc bounds (edges missing or disabled
+// included) and supply/demand conservation. Entries on the same arc are
+// added up before the bounds are checked. Lower bounds are hard bounds
+// (as in the solvers), arcs left out of the flow carry 0; fixed costs are
+// paid once by arcs with positive flow. Solvers leave out
+// flows below 0.01, each vertex tolerates 0.01 per arc.
+func Verify(g *fct.Graph, flow []*EdgeFlow) *Verification {
+	result := &Verification{Violations: make([]string, 0)}
+	violation := func(format string, a ...interface{}) {
+		result.Violations = append(result.Violations, fmt.Sprintf(format, a...))
+	}
+	shipped := make(map[int]float64)
+	received := make(map[int]float64)
+	amount := make(map[*fct.Edge]float64)
+	for _, f := range flow {
+		e := g.Arc(f.Source, f.Sink)
+		if e == nil || !g.Enabled(e) {
+			violation("Flow on missing edge %s", f)
+			continue
+		}
+		if f.Amount < 0 {
+			violation("Negative flow %s", f)
+		}
+		result.VCost += f.Amount * g.Cost(e)
+		amount[e] += f.Amount
+		shipped[f.Source] += f.Amount
+		received[f.Sink] += f.Amount
+	}
+	for _, e := range g.Edges {
+		if !g.Enabled(e) {
+			continue
+		}
+		f := &EdgeFlow{e.I.Data.Id, e.J.Data.Id, amount[e]}
+		if c := g.Capacity(e); f.Amount > c+1e-6 {
+			violation("Flow above capacity %s [%.2f]", f, c)
+		}
+		if l := e.Data.Lower; l > 0 && f.Amount < l-1e-6 {
+			violation("Flow below lower bound %s [%.2f]", f, l)
+		}
+		if f.Amount > 0 {
+			result.Arcs++
+			result.FCost += e.Data.FCost
+		}
+	}
+	result.Total = result.VCost + result.FCost
+	for _, id := range sortedIds(g.Sources) {
+		v := g.Sources[id]
+		if d := shipped[id] - v.Data.Size; math.Abs(d) > 0.01*float64(len(v.EdgeOut)+1) {
+			violation("Source %d shipped %.2f of %.2f", id, shipped[id], v.Data.Size)
+		}
+	}
+	for _, id := range sortedIds(g.Sinks) {
+		v := g.Sinks[id]
+		if d := received[id] - v.Data.Size; math.Abs(d) > 0.01*float64(len(v.EdgeIn)+1) {
+			violation("Sink %d received %.2f of %.2f", id, received[id], v.Data.Size)
+		}
+	}
+	return result
+}
+
+func sortedIds(m map[int]*fct.Vertex) []int {
+	result := make([]int, 0, len(m))
+	for id := range m {
+		result = append(result, id)
+	}
+	sort.Ints(result)
+	return result
+}
+
+// VerifyFlow checks the streams of a game result, streams of several owners
+// on the same edge are added up.
+func VerifyFlow(g *fct.Graph, f *Flow) *Verification {
+	return Verify(g, StreamFlow(f))
+}
+
+// StreamFlow is the flow by edge (in order of first stream) of a game result.
+func StreamFlow(f *Flow) []*EdgeFlow {
+	result := make([]*EdgeFlow, 0, len(f.Streams))
+	edges := make(map[fct.Key]*EdgeFlow)
+	for _, s := range f.Streams {
+		key := fct.Key{Source: s.Source, Sink: s.Sink}
+		if ef, found := edges[key]; found {
+			ef.Amount += s.Amount
+			continue
+		}
+		ef := &EdgeFlow{s.Source, s.Sink, s.Amount}
+		edges[key] = ef
+		result = append(result, ef)
+	}
+	return result
+}
+
+// ReadFlow parses a flow file, one edge per line: "source sink amount" or
+// a stream line of the game protocol (amount last). Blank lines, comments
+// (#) and headers (2 fields, e.g. "FLOW k") are skipped.
+func ReadFlow(r io.Reader) ([]*EdgeFlow, error) {
+	result := make([]*EdgeFlow, 0)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		n := strings.Fields(text)
+		if len(n) == 0 || len(n) == 2 || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if len(n) != 3 && len(n) != 6 {
+			return nil, fmt.Errorf("Wrong number of fields (3 or 6) in line %d: %d", line, len(n))
+		}
+		source, err := strconv.ParseInt(n[0], 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing source in line %d: %s", line, err)
+		}
+		sink, err := strconv.ParseInt(n[1], 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing sink in line %d: %s", line, err)
+		}
+		amount, err := strconv.ParseFloat(n[len(n)-1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing amount in line %d: %s", line, err)
+		}
+		result = append(result, &EdgeFlow{int(source), int(sink), amount})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WriteFlow is the flow file read by ReadFlow.
+func WriteFlow(w io.Writer, flow []*EdgeFlow) error {
+	for _, f := range flow {
+		if _, err := fmt.Fprintf(w, "%d %d %g\n", f.Source, f.Sink, f.Amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"bytes"
+	"parallax/fct"
+	"strings"
+	"testing"
+)
+
+func verifyGraph() *fct.Graph {
+	g := fct.NewGraph()
+	g.SourceSize(1, 5.)
+	g.SourceSize(2, 5.)
+	g.SinkSize(3, 6.)
+	g.SinkSize(4, 4.)
+	g.NewEdge(1, 3, 1., 10.)
+	g.NewEdge(1, 4, 2., 20.)
+	g.NewEdge(2, 3, 3., 30.)
+	g.NewEdge(2, 4, 4., 40.)
+	g.SetBounds(2, 3, 2., 3.)
+	return g
+}
+
+func TestVerify(t *testing.T) {
+	g := verifyGraph()
+	flow := []*EdgeFlow{{1, 3, 3.}, {1, 4, 2.}, {2, 3, 3.}, {2, 4, 2.}, {2, 4, 0.}}
+	v := Verify(g, flow)
+	if !v.Feasible() || v.Arcs != 4 {
+		t.Fatal("Wrong verification:", v)
+	}
+	if v.VCost != 3.+4.+9.+8. || v.FCost != 100. || v.Total != v.VCost+v.FCost {
+		t.Error("Wrong cost:", v)
+	}
+	if v.Total != Objective(g, flow) || v.VCost != FlowCost(g, flow) {
+		t.Error("Cost differs from objective:", v)
+	}
+
+	// entries on the same arc added up, the arc charged once
+	flow = []*EdgeFlow{{1, 3, 3.}, {1, 4, 2.}, {2, 3, 3.}, {2, 4, 1.}, {2, 4, 1.}}
+	if v := Verify(g, flow); !v.Feasible() || v.Arcs != 4 || v.FCost != 100. || v.Total != Objective(g, flow) {
+		t.Error("Wrong verification of duplicate entries:", v, Objective(g, flow))
+	}
+	v = Verify(g, []*EdgeFlow{{2, 4, 3.}, {2, 4, 3.}})
+	if v.Arcs != 1 || v.FCost != 40. || !strings.Contains(v.String(), "above capacity (2)-[6.00]->(4) [4.00]") {
+		t.Error("Wrong violations for duplicate entries:", v)
+	}
+
+	// negative, missing edge, above capacity, below lower bound, conservation
+	flow = []*EdgeFlow{{1, 3, 6.}, {2, 3, 1.}, {2, 4, -1.}, {4, 1, 1.}}
+	v = Verify(g, flow)
+	expected := []string{"Negative", "missing edge", "above capacity", "below lower bound", "Source 1", "Source 2", "Sink 3", "Sink 4"}
+	if len(v.Violations) != len(expected) {
+		t.Fatal("Wrong violations:", v)
+	}
+	for i, s := range expected {
+		if !strings.Contains(v.Violations[i], s) {
+			t.Error("Wrong violation:", v.Violations[i], "expected", s)
+		}
+	}
+
+	// lower bounds are hard, arcs left out of the flow carry 0
+	flow = []*EdgeFlow{{1, 3, 5.}, {2, 3, 1.}, {2, 4, 4.}}
+	g.SetBounds(2, 3, 0., 3.)
+	if v := Verify(g, flow); !v.Feasible() {
+		t.Error("Wrong verification:", v)
+	}
+	g.SetBounds(1, 4, 1., 4.)
+	v = Verify(g, flow)
+	if len(v.Violations) != 1 || !strings.Contains(v.Violations[0], "(1)-[0.00]->(4)") {
+		t.Error("Wrong violations for lower bound left out:", v)
+	}
+	g.SetBounds(1, 4, 0., 4.)
+	g.SetBounds(2, 3, 2., 3.)
+
+	// disabled edges are missing
+	o := g.Overlay()
+	o.Disable(1, 4)
+	if v := Verify(o, []*EdgeFlow{{1, 4, 2.}}); !strings.Contains(v.Violations[0], "missing edge") {
+		t.Error("Wrong violations for disabled edge:", v)
+	}
+}
+
+func TestVerifyFlow(t *testing.T) {
+	g := verifyGraph()
+	f := &Flow{[]*Stream{{1, 3, 2., "a", 1., 1}, {1, 4, 2., "a", 1., 1}, {1, 3, 1., "b", 1., 1}, {2, 3, 3., "b", 1., 1}, {2, 4, 2., "b", 1., 1}}}
+	flow := StreamFlow(f)
+	if len(flow) != 4 || flow[0].Amount != 3. {
+		t.Error("Wrong stream flow:", flow)
+	}
+	if v := VerifyFlow(g, f); !v.Feasible() || v.Total != 124. {
+		t.Error("Wrong verification:", v)
+	}
+}
+
+func TestReadFlow(t *testing.T) {
+	flow := []*EdgeFlow{{1, 3, 3.}, {1, 4, 2.5}}
+	var buf bytes.Buffer
+	if err := WriteFlow(&buf, flow); err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteString("\n# streams\nFLOW 1\n2 3 b 1 10.00 3\n")
+	read, err := ReadFlow(&buf)
+	if err != nil {
+		t.Fatal("Error reading flow:", err)
+	}
+	if len(read) != 3 || read[1].Amount != 2.5 || read[2].Source != 2 || read[2].Amount != 3. {
+		t.Error("Wrong flow:", read)
+	}
+	if _, err := ReadFlow(strings.NewReader("1 3 x\n")); err == nil {
+		t.Error("No error for bad amount")
+	}
+	if _, err := ReadFlow(strings.NewReader("1 3 4 5\n")); err == nil {
+		t.Error("No error for wrong number of fields")
+	}
+}
@@ -2,44 +2,53 @@ package engine
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"parallax/core"
 	"parallax/fct"
 )
 
+// Instances (overlays updated with game prices) kept by each engine
+const ENGINE_CACHE_SIZE = 32
+
 type graphEngine struct {
 	graphs  fct.GraphLoader
-	data    map[string]*fct.Graph
+	data    *fct.Cache
 	current *fct.Graph
+	log     io.Writer
 }
 
 func newGraphEngine(g fct.GraphLoader) *graphEngine {
 	return &graphEngine{
 		g,
-		make(map[string]*fct.Graph),
+		fct.NewCache(ENGINE_CACHE_SIZE),
 		nil,
+		os.Stdout,
 	}
 }
 
+func (n *graphEngine) SetLog(w io.Writer) {
+	n.log = w
+}
+
+// The overlay is versioned by the loaded instance, a reloaded file starts over.
 func (n *graphEngine) setup(name string) {
-	if g, found := n.data[name]; found {
-		n.current = g
-		return
-	}
 	n.current = nil
 	g := n.graphs.Instance(name)
 	if g == nil {
 		return
 	}
-	n.current = g.Clone()
-	n.data[name] = n.current
+	n.current, _ = n.data.Get(name, g, func() (*fct.Graph, error) {
+		return g.Overlay(), nil
+	})
 }
 
 func (n *graphEngine) Update(f *core.Flow) {
 	if n.current == nil {
-		fmt.Println("Instance not found")
+		fmt.Fprintln(n.log, "Instance not found")
 		return
 	}
 	for _, s := range f.Streams {
-		n.current.EdgeCost(s.Source, s.Sink, s.Price)
+		n.current.SetCost(s.Source, s.Sink, s.Price)
 	}
 }
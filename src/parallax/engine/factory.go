@@ -3,12 +3,17 @@ package engine
 import (
 	"parallax/core"
 	"parallax/fct"
+	"parallax/heuristic"
+	"strings"
 )
 
 const (
 	BID_RANDOM_EDGES string = "RandomEdges"
 	BID_FIRST_EDGES         = "FirstEdges"
 	BID_GUROBI_EDGES string = "GurobiEdges"
+	// <Solver>Edges: flow of a core solver or heuristic (e.g. SSPEdges,
+	// VogelEdges, SlopeScalingEdges, TabuEdges)
+	BID_SOLVER_EDGES = "Edges"
 )
 
 func New(name string, graphs fct.GraphLoader, factor float64) core.BidEngine {
@@ -20,6 +25,13 @@ func New(name string, graphs fct.GraphLoader, factor float64) core.BidEngine {
 	case BID_GUROBI_EDGES:
 		return NewGurobiEdges(graphs, factor)
 	default:
+		if !strings.HasSuffix(name, BID_SOLVER_EDGES) {
+			return nil
+		}
+		solver := strings.TrimSuffix(name, BID_SOLVER_EDGES)
+		if s := heuristic.NewSolver(solver); s != nil {
+			return NewSolverEdges(graphs, factor, solver, s)
+		}
 		return nil
 	}
 }
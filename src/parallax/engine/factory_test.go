@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"io"
+	"parallax/conformance"
+	"parallax/core"
+	"parallax/fct"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	g := conformance.Generate(1, 3, 4)
+	graphs := fct.NewStaticLoader(map[string]*fct.Graph{"T1": g})
+	for _, name := range []string{"Unknown", BID_SOLVER_EDGES, "UnknownEdges", "Tabu"} {
+		if n := New(name, graphs, 2.); n != nil {
+			t.Error("Engine for unknown name:", name)
+		}
+	}
+	if New(BID_RANDOM_EDGES, graphs, 2.) == nil || New(BID_FIRST_EDGES, graphs, 2.) == nil || New(BID_GUROBI_EDGES, graphs, 2.) == nil {
+		t.Error("Missing engine")
+	}
+	for _, name := range []string{"SSPEdges", "CostScalingEdges", "VogelEdges", "FixedChargeEdges", "SlopeScalingEdges", "TabuEdges"} {
+		n := New(name, graphs, 2.)
+		if n == nil {
+			t.Error("Missing engine:", name)
+			continue
+		}
+		n.(core.Logged).SetLog(io.Discard)
+		if bids := n.ComputeBid(&core.Match{InstanceName: "T1", NumberOfEdges: 3}); bids.String() == core.EmptyBidPack().String() {
+			t.Error(name, "no bids")
+		}
+	}
+}
+
+func TestSolverEdgesBalance(t *testing.T) {
+	g := conformance.Generate(1, 3, 4)
+	g.SourceSize(1, g.Sources[1].Data.Size+10.)
+	graphs := fct.NewStaticLoader(map[string]*fct.Graph{"T1": g})
+	m := &core.Match{InstanceName: "T1", NumberOfEdges: 3}
+	n := New("SSPEdges", graphs, 2.)
+	n.(core.Logged).SetLog(io.Discard)
+	if bids := n.ComputeBid(m); bids.String() != core.EmptyBidPack().String() {
+		t.Error("Bids for unbalanced instance:", bids)
+	}
+	n.(core.Balanced).SetBalance(100.)
+	if bids := n.ComputeBid(m); bids.String() == core.EmptyBidPack().String() {
+		t.Error("No bids with balance")
+	}
+	if name := n.(*SolverEdges).name; name != "SSP/Balanced100" {
+		t.Error("Wrong result name:", name)
+	}
+}
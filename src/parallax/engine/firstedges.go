@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"parallax/core"
 	"parallax/fct"
-	"parallax/graph"
 )
 
 type FirstEdges struct {
@@ -19,7 +18,7 @@ func NewFirstEdges(g fct.GraphLoader, factor float64) core.BidEngine {
 func (n *FirstEdges) ComputeBid(m *core.Match) *core.BidPack {
 	n.setup(m.InstanceName)
 	if n.current == nil {
-		fmt.Println("Instance not found:", m.InstanceName)
+		fmt.Fprintln(n.log, "Instance not found:", m.InstanceName)
 		return core.EmptyBidPack()
 	}
 	pack := core.NewBidPack(m.NumberOfEdges)
@@ -31,10 +30,10 @@ func (n *FirstEdges) ComputeBid(m *core.Match) *core.BidPack {
 	return pack
 }
 
-func (n *FirstEdges) bid(e *graph.Edge) (int, int, float64) {
-	source := e.I.Data.(*fct.VertexData).Id
-	sink := e.J.Data.(*fct.VertexData).Id
-	price := e.Data.(*fct.EdgeData).VCost
+func (n *FirstEdges) bid(e *fct.Edge) (int, int, float64) {
+	source := e.I.Data.Id
+	sink := e.J.Data.Id
+	price := n.current.Cost(e)
 	factor := n.factor
 	return source, sink, factor * price
 }
@@ -5,7 +5,6 @@ import (
 	"math/rand"
 	"parallax/core"
 	"parallax/fct"
-	"parallax/graph"
 	"time"
 )
 
@@ -26,7 +25,7 @@ func NewRandomEdges(g fct.GraphLoader, factor float64) core.BidEngine {
 func (n *RandomEdges) ComputeBid(m *core.Match) *core.BidPack {
 	n.setup(m.InstanceName)
 	if n.current == nil {
-		fmt.Println("Instance not found:", m.InstanceName)
+		fmt.Fprintln(n.log, "Instance not found:", m.InstanceName)
 		return core.EmptyBidPack()
 	}
 
@@ -47,10 +46,10 @@ func (n *RandomEdges) ComputeBid(m *core.Match) *core.BidPack {
 	return pack
 }
 
-func (n *RandomEdges) bid(e *graph.Edge) (int, int, float64) {
-	source := e.I.Data.(*fct.VertexData).Id
-	sink := e.J.Data.(*fct.VertexData).Id
-	price := e.Data.(*fct.EdgeData).VCost
+func (n *RandomEdges) bid(e *fct.Edge) (int, int, float64) {
+	source := e.I.Data.Id
+	sink := e.J.Data.Id
+	price := n.current.Cost(e)
 	factor := 1. + float64(n.frnd.Intn(n.maxFactor))
 	return source, sink, factor * price
 }
@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"parallax/core"
+	"parallax/fct"
+	"sort"
+)
+
+// Bids on the edges of the flow computed by a solver (Gurobi or any core
+// solver by name, e.g. VogelEdges).
+type SolverEdges struct {
+	*graphEngine
+	factor float64
+	name   string // solver, key of the shared results
+	solver core.Solver
+}
+
+func NewGurobiEdges(g fct.GraphLoader, factor float64) core.BidEngine {
+	// instances are re-solved after each Update, warm started per instance
+	return NewSolverEdges(g, factor, core.SOLVER_GUROBI, core.NewSessionSolver(core.SESSION_CAPACITY))
+}
+
+func NewSolverEdges(g fct.GraphLoader, factor float64, name string, solver core.Solver) core.BidEngine {
+	return &SolverEdges{
+		newGraphEngine(g),
+		factor,
+		name,
+		solver,
+	}
+}
+
+// Solver messages go to the engine log.
+func (n *SolverEdges) SetLog(w io.Writer) {
+	n.graphEngine.SetLog(w)
+	if s, ok := n.solver.(core.Logged); ok {
+		s.SetLog(w)
+	}
+}
+
+// Set before Instrument and SetResultCache, balanced results are cached
+// apart.
+func (n *SolverEdges) SetBalance(penalty float64) {
+	n.solver = core.NewBalancedSolver(n.solver, penalty)
+	n.name = fmt.Sprintf("%s/Balanced%g", n.name, penalty)
+}
+
+func (n *SolverEdges) Instrument(m *core.PlayerMetrics) {
+	n.solver = core.NewTimedSolver(n.solver, m)
+}
+
+func (n *SolverEdges) SetResultCache(c *core.ResultCache) {
+	n.solver = core.NewCachedSolver(n.solver, n.name, c)
+}
+
+func (n *SolverEdges) ComputeBid(m *core.Match) *core.BidPack {
+	n.setup(m.InstanceName)
+	if n.current == nil {
+		fmt.Fprintln(n.log, "Instance not found:", m.InstanceName)
+		return core.EmptyBidPack()
+	}
+	flow, err := n.solver.ComputeFlow(n.current)
+	if err != nil {
+		fmt.Fprintln(n.log, "Error computing flow:", err)
+		return core.EmptyBidPack()
+	}
+	//sort.Sort(core.FlowSort(flow))
+	sort.Sort(NewProfitSort(n.current, flow))
+	pack := core.NewBidPack(m.NumberOfEdges)
+	factor := n.factor
+	max := 100 //m.NumberOfEdges
+	for i, k := len(flow)-1, 0; k < max && i > -1; i, k = i-1, k+1 {
+		ef := flow[i]
+		e := n.current.Arc(ef.Source, ef.Sink)
+		price := n.current.Cost(e)
+		pack.Bid(ef.Source, ef.Sink, price*factor)
+	}
+	return pack
+}
+
+type ProfitSort struct {
+	profit []float64
+	flow   []*core.EdgeFlow
+}
+
+func NewProfitSort(g *fct.Graph, flow []*core.EdgeFlow) *ProfitSort {
+	profit := make([]float64, len(flow))
+	for i, ef := range flow {
+		e := g.Arc(ef.Source, ef.Sink)
+		_e := e.Data
+		v, f := g.Cost(e), _e.FCost
+		profit[i] = ef.Amount*v - f
+	}
+	return &ProfitSort{profit, flow}
+}
+
+func (v *ProfitSort) Len() int           { return len(v.flow) }
+func (v *ProfitSort) Less(i, j int) bool { return v.profit[i] < v.profit[j] }
+
+func (v *ProfitSort) Swap(i, j int) {
+	v.profit[i], v.profit[j] = v.profit[j], v.profit[i]
+	v.flow[i], v.flow[j] = v.flow[j], v.flow[i]
+}
@@ -0,0 +1,182 @@
+package fct
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Instance sources - selected by URI scheme
+//
+//	./data, file://./data     directory with .DAT and .DAT.gz files
+//	zip://data.zip            zip archive (or any path ending with .zip)
+//	tar://data.tar.gz         gzip compressed tar archive (.tar.gz, .tgz)
+//	embed://                  bundle compiled into the binary
+
+func OpenLoader(uri string, verbose int, capacity int) (*FileLoader, error) {
+	scheme, p := "file", uri
+	if i := strings.Index(uri, "://"); i > 0 {
+		scheme, p = uri[:i], uri[i+3:]
+	} else if strings.HasSuffix(uri, ".zip") {
+		scheme = "zip"
+	} else if strings.HasSuffix(uri, ".tar.gz") || strings.HasSuffix(uri, ".tgz") {
+		scheme = "tar"
+	}
+
+	var fsys fs.FS
+	var err error
+	switch scheme {
+	case "file":
+		fsys = os.DirFS(p)
+	case "zip":
+		fsys, err = OpenZip(p)
+	case "tar":
+		fsys, err = OpenTar(p)
+	case "embed":
+		fsys, err = bundle()
+	default:
+		err = fmt.Errorf("Unknown data scheme: %s", scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewFSLoader(fsys, uri, verbose, capacity), nil
+}
+
+var bundleLock sync.Mutex
+var bundleFS fs.FS
+
+// RegisterBundle sets the file system served by embed:// (see package bundle).
+func RegisterBundle(fsys fs.FS) {
+	bundleLock.Lock()
+	defer bundleLock.Unlock()
+	bundleFS = fsys
+}
+
+func bundle() (fs.FS, error) {
+	bundleLock.Lock()
+	defer bundleLock.Unlock()
+	if bundleFS == nil {
+		return nil, fmt.Errorf("No instance bundle compiled into the binary")
+	}
+	return bundleFS, nil
+}
+
+// Archive entries are kept in memory by base name, folders are flattened.
+func OpenZip(p string) (fs.FS, error) {
+	r, err := zip.OpenReader(p)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	m := make(memFS)
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Error reading %s: %s", f.Name, err)
+		}
+		m.add(f.Name, data, f.Modified)
+	}
+	return m, nil
+}
+
+func OpenTar(p string) (fs.FS, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	m := make(memFS)
+	r := tar.NewReader(gz)
+	for {
+		h, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading %s: %s", h.Name, err)
+		}
+		m.add(h.Name, data, h.ModTime)
+	}
+	return m, nil
+}
+
+type memFS map[string]*memFile
+
+func (m memFS) add(name string, data []byte, modTime time.Time) {
+	name = path.Base(name)
+	m[name] = &memFile{name, data, modTime}
+}
+
+func (m memFS) Open(name string) (fs.File, error) {
+	if f, found := m[name]; found {
+		return &openFile{f, bytes.NewReader(f.data)}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (m memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	result := make([]fs.DirEntry, 0, len(m))
+	for _, f := range m {
+		result = append(result, fs.FileInfoToDirEntry(f))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name() < result[j].Name() })
+	return result, nil
+}
+
+type memFile struct {
+	name    string
+	data    []byte
+	modTime time.Time
+}
+
+func (f *memFile) Name() string       { return f.name }
+func (f *memFile) Size() int64        { return int64(len(f.data)) }
+func (f *memFile) Mode() fs.FileMode  { return 0444 }
+func (f *memFile) ModTime() time.Time { return f.modTime }
+func (f *memFile) IsDir() bool        { return false }
+func (f *memFile) Sys() interface{}   { return nil }
+
+type openFile struct {
+	*memFile
+	r *bytes.Reader
+}
+
+func (f *openFile) Stat() (fs.FileInfo, error) { return f.memFile, nil }
+func (f *openFile) Read(p []byte) (int, error) { return f.r.Read(p) }
+func (f *openFile) Close() error               { return nil }
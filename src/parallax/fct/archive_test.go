@@ -0,0 +1,77 @@
+package fct
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"testing"
+	"time"
+)
+
+func testArchiveLoader(t *testing.T, uri string) {
+	d, err := OpenLoader(uri, 0, 0)
+	if err != nil {
+		t.Fatal("Error opening loader:", uri, err)
+	}
+	g := d.Instance("N104")
+	if g == nil {
+		t.Fatal("Instance not found:", uri)
+	}
+	if n := g.Size(); n != 100 {
+		t.Error("Wrong number of arks (100):", uri, n)
+	}
+	m, err := d.LoadAll(2)
+	if err != nil {
+		t.Error("Error loading all:", uri, err)
+	}
+	if n := len(m.Instances); n != 1 {
+		t.Error("Wrong number of instances (1):", uri, n)
+	}
+}
+
+func TestLoadZip(t *testing.T) {
+	data, _ := os.ReadFile("N104.DAT")
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, _ := w.Create("instances/N104.DAT")
+	f.Write(data)
+	w.Close()
+	file := t.TempDir() + "/data.zip"
+	os.WriteFile(file, buf.Bytes(), 0644)
+	testArchiveLoader(t, file)
+	testArchiveLoader(t, "zip://"+file)
+}
+
+func TestLoadTar(t *testing.T) {
+	data, _ := os.ReadFile("N104.DAT")
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	w.WriteHeader(&tar.Header{Name: "N104.DAT", Mode: 0644, Size: int64(len(data)), ModTime: time.Now()})
+	w.Write(data)
+	w.Close()
+	gz.Close()
+	file := t.TempDir() + "/data.tar.gz"
+	os.WriteFile(file, buf.Bytes(), 0644)
+	testArchiveLoader(t, "tar://"+file)
+}
+
+func TestLoadGzip(t *testing.T) {
+	data, _ := os.ReadFile("N104.DAT")
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(data)
+	gz.Close()
+	dir := t.TempDir()
+	os.WriteFile(dir+"/N104.DAT.gz", buf.Bytes(), 0644)
+	testArchiveLoader(t, dir)
+	testArchiveLoader(t, "file://"+dir)
+}
+
+func TestLoadEmbedMissing(t *testing.T) {
+	if _, err := OpenLoader("embed://", 0, 0); err == nil {
+		t.Error("Missing error without bundle")
+	}
+}
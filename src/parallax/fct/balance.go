@@ -0,0 +1,84 @@
+package fct
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Unbalanced and infeasible instances
+
+// Infeasible names the sources and sinks whose supply or demand cannot be
+// met, from the maximum flow over the enabled edges.
+type Infeasible struct {
+	*MaxFlow
+}
+
+func (e *Infeasible) Error() string {
+	out := fmt.Sprintf("Infeasible, max flow %.2f of %.2f", e.Value, math.Max(e.Supply, e.Demand))
+	if math.Abs(e.Supply-e.Demand) > 0.001 {
+		out += fmt.Sprintf(", unbalanced supply %.2f and demand %.2f", e.Supply, e.Demand)
+	}
+	if len(e.Sources) > 0 {
+		out += ", sources" + shortfall(e.Sources)
+	}
+	if len(e.Sinks) > 0 {
+		out += ", sinks" + shortfall(e.Sinks)
+	}
+	return out
+}
+
+func shortfall(m map[int]float64) string {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	out := ""
+	for _, id := range ids {
+		out += fmt.Sprintf(" %d (%.2f)", id, m[id])
+	}
+	return out
+}
+
+// Diagnose returns an *Infeasible error, nil if all supply can be shipped
+// and all demand met.
+func (g *Graph) Diagnose() error {
+	f := g.MaxFlow(nil)
+	if f.Feasible {
+		return nil
+	}
+	return &Infeasible{f}
+}
+
+// Balance adds a dummy sink (excess supply) or a dummy source (excess demand)
+// connected to every sink or source with the penalty as variable cost. The
+// result is a clone with the dummy vertex, or g itself (nil vertex) if already
+// balanced.
+func (g *Graph) Balance(penalty float64) (*Graph, *Vertex) {
+	supply, demand := g.Supply(), g.Demand()
+	if math.Abs(supply-demand) <= 0.001 {
+		return g, nil
+	}
+	result := g.Clone()
+	id := 1
+	for _, m := range []map[int]*Vertex{g.Sources, g.Sinks} {
+		for i := range m {
+			if i >= id {
+				id = i + 1
+			}
+		}
+	}
+	if supply > demand {
+		dummy := result.SinkSize(id, supply-demand)
+		for _, source := range vertexIds(g.Sources) {
+			result.NewEdge(source, id, penalty, 0.)
+		}
+		return result, dummy
+	}
+	dummy := result.SourceSize(id, demand-supply)
+	for _, sink := range vertexIds(g.Sinks) {
+		result.NewEdge(id, sink, penalty, 0.)
+	}
+	return result, dummy
+}
@@ -0,0 +1,187 @@
+package fct
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// Cache - LRU of values T, safe for concurrent use, one load per name at a time
+
+type CacheStats struct {
+	Hits, Misses, Loads, Errors int
+	Evictions, Invalidations    int
+	Size, Capacity              int
+}
+
+func (s CacheStats) String() string {
+	return fmt.Sprintf("Hits %d, Misses %d, Loads %d, Errors %d, Evictions %d, Invalidations %d, Size %d/%d",
+		s.Hits, s.Misses, s.Loads, s.Errors, s.Evictions, s.Invalidations, s.Size, s.Capacity)
+}
+
+type TypedCache[T any] struct {
+	mu       sync.Mutex
+	capacity int
+	lru      *list.List
+	entries  map[string]*list.Element
+	calls    map[string]*cacheCall[T]
+	stats    CacheStats
+	onEvict  func(name string, value T)
+}
+
+// Graph cache (instances by name)
+type Cache = TypedCache[*Graph]
+
+type cacheEntry[T any] struct {
+	name    string
+	version interface{}
+	value   T
+}
+
+type cacheCall[T any] struct {
+	done    chan struct{}
+	version interface{}
+	value   T
+	err     error
+}
+
+// Capacity <= 0 means unbounded.
+func NewCache(capacity int) *Cache {
+	return NewTypedCache[*Graph](capacity)
+}
+
+func NewTypedCache[T any](capacity int) *TypedCache[T] {
+	return &TypedCache[T]{
+		capacity: capacity,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+		calls:    make(map[string]*cacheCall[T]),
+	}
+}
+
+// Get returns the cached value if its version matches (==), otherwise loads it.
+// Concurrent calls for the same name and version share one load.
+func (c *TypedCache[T]) Get(name string, version interface{}, load func() (T, error)) (T, error) {
+	c.mu.Lock()
+	if el, found := c.entries[name]; found {
+		entry := el.Value.(*cacheEntry[T])
+		if entry.version == version {
+			c.lru.MoveToFront(el)
+			c.stats.Hits++
+			c.mu.Unlock()
+			return entry.value, nil
+		}
+		c.remove(el)
+		c.stats.Invalidations++
+	}
+	c.stats.Misses++
+	if call, found := c.calls[name]; found && call.version == version {
+		c.mu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+	call := &cacheCall[T]{done: make(chan struct{}), version: version}
+	c.calls[name] = call
+	c.mu.Unlock()
+
+	call.value, call.err = load()
+
+	c.mu.Lock()
+	latest := c.calls[name] == call
+	if latest {
+		delete(c.calls, name)
+	}
+	if call.err != nil {
+		c.stats.Errors++
+	} else {
+		c.stats.Loads++
+		if latest {
+			c.put(name, version, call.value)
+		}
+	}
+	c.mu.Unlock()
+	close(call.done)
+	return call.value, call.err
+}
+
+// Put stores the value (e.g. restored from disk) as the most recently used.
+func (c *TypedCache[T]) Put(name string, version interface{}, value T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.put(name, version, value)
+}
+
+// Each visits the entries from the least to the most recently used.
+func (c *TypedCache[T]) Each(f func(name string, value T)) {
+	c.mu.Lock()
+	entries := make([]*cacheEntry[T], 0, c.lru.Len())
+	for el := c.lru.Back(); el != nil; el = el.Prev() {
+		entries = append(entries, el.Value.(*cacheEntry[T]))
+	}
+	c.mu.Unlock()
+	for _, entry := range entries {
+		f(entry.name, entry.value)
+	}
+}
+
+// OnEvict sets f, called with each value leaving the cache (evicted,
+// invalidated or replaced) to release it. f runs under the cache lock and
+// must not use the cache.
+func (c *TypedCache[T]) OnEvict(f func(name string, value T)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onEvict = f
+}
+
+func (c *TypedCache[T]) put(name string, version interface{}, value T) {
+	if el, found := c.entries[name]; found {
+		c.remove(el)
+	}
+	c.entries[name] = c.lru.PushFront(&cacheEntry[T]{name, version, value})
+	for c.capacity > 0 && c.lru.Len() > c.capacity {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *TypedCache[T]) remove(el *list.Element) {
+	entry := el.Value.(*cacheEntry[T])
+	c.lru.Remove(el)
+	delete(c.entries, entry.name)
+	if c.onEvict != nil {
+		c.onEvict(entry.name, entry.value)
+	}
+}
+
+func (c *TypedCache[T]) Invalidate(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, found := c.entries[name]; found {
+		c.remove(el)
+		c.stats.Invalidations++
+	}
+}
+
+func (c *TypedCache[T]) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Invalidations += c.lru.Len()
+	for c.lru.Len() > 0 {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *TypedCache[T]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *TypedCache[T]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Size = c.lru.Len()
+	s.Capacity = c.capacity
+	return s
+}
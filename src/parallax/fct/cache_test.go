@@ -0,0 +1,122 @@
+package fct
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCacheHitsMisses(t *testing.T) {
+	c := NewCache(2)
+	loads := 0
+	load := func() (*Graph, error) {
+		loads++
+		return NewGraph(), nil
+	}
+	g1, _ := c.Get("a", 1, load)
+	g2, _ := c.Get("a", 1, load)
+	if g1 != g2 || loads != 1 {
+		t.Error("Instance not cached (1):", loads)
+	}
+	c.Get("a", 2, load)
+	if loads != 2 {
+		t.Error("Changed instance not reloaded (2):", loads)
+	}
+	c.Get("b", 1, load)
+	c.Get("c", 1, load)
+	if n := c.Len(); n != 2 {
+		t.Error("Cache over capacity (2):", n)
+	}
+	c.Get("a", 2, load)
+	if loads != 5 {
+		t.Error("Least recently used not evicted (5):", loads)
+	}
+	c.Invalidate("a")
+	c.Get("a", 2, load)
+	if loads != 6 {
+		t.Error("Invalidated instance not reloaded (6):", loads)
+	}
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 6 || s.Evictions != 2 || s.Invalidations != 2 {
+		t.Error("Wrong statistics:", s)
+	}
+}
+
+func TestCacheOnEvict(t *testing.T) {
+	c := NewTypedCache[int](2)
+	released := make([]int, 0)
+	c.OnEvict(func(name string, value int) {
+		released = append(released, value)
+	})
+	load := func(v int) func() (int, error) {
+		return func() (int, error) { return v, nil }
+	}
+	c.Get("a", 1, load(1))
+	c.Get("b", 1, load(2))
+	c.Get("c", 1, load(3)) // evicts a
+	c.Get("b", 2, load(4)) // invalidates b
+	c.Put("c", 1, 5)       // replaces c
+	c.InvalidateAll()
+	expected := []int{1, 2, 3, 4, 5}
+	if len(released) != len(expected) {
+		t.Fatal("Wrong values released:", released)
+	}
+	for i, v := range expected {
+		if released[i] != v {
+			t.Error("Wrong values released:", released)
+			break
+		}
+	}
+}
+
+func TestCacheSingleLoad(t *testing.T) {
+	c := NewCache(0)
+	var loads int32
+	start := make(chan struct{})
+	load := func() (*Graph, error) {
+		atomic.AddInt32(&loads, 1)
+		<-start
+		return NewGraph(), nil
+	}
+	var wg sync.WaitGroup
+	results := make([]*Graph, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = c.Get("a", 1, load)
+		}(i)
+	}
+	for c.Stats().Misses < len(results) {
+		// wait all callers (blocked on the load)
+		runtime.Gosched()
+	}
+	close(start)
+	wg.Wait()
+	if n := atomic.LoadInt32(&loads); n != 1 {
+		t.Error("Concurrent loads (1):", n)
+	}
+	for _, g := range results {
+		if g != results[0] {
+			t.Error("Different instances returned")
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	g := testGraph(3)
+	o := g.Overlay()
+	if g.Fingerprint() != o.Fingerprint() || g.Fingerprint() != g.Clone().Fingerprint() {
+		t.Error("Different fingerprints for equal graphs")
+	}
+	o.SetCost(1, 4, 0.)
+	if g.Fingerprint() == o.Fingerprint() || o.Fingerprint() != o.Clone().Fingerprint() {
+		t.Error("Wrong fingerprint after cost change")
+	}
+	o = g.Overlay()
+	o.Disable(1, 4)
+	if g.Fingerprint() == o.Fingerprint() {
+		t.Error("Same fingerprint with disabled edge")
+	}
+}
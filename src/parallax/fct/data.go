@@ -2,10 +2,16 @@ package fct
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // FCT format
@@ -16,7 +22,23 @@ func LoadGraph(path string, verbose int) (*Graph, error) {
 		return nil, err
 	}
 	defer file.Close()
+	if strings.HasSuffix(path, ".gz") {
+		r, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return ReadGraph(r, verbose)
+	}
+	return ReadGraph(file, verbose)
+}
 
+func ReadGraph(r io.Reader, verbose int) (*Graph, error) {
+	return readGraph(r, verbose, os.Stdout)
+}
+
+// Parser messages are written to log.
+func readGraph(r io.Reader, verbose int, log io.Writer) (*Graph, error) {
 	g := NewGraph()
 
 	type GraphParser int
@@ -27,14 +49,14 @@ func LoadGraph(path string, verbose int) (*Graph, error) {
 	)
 
 	parser := EDGES
-	scan := bufio.NewScanner(file)
+	scan := bufio.NewScanner(r)
 	scan.Scan() //skip line 1
 	scan.Scan() //skip line 2
 	scan.Scan() //skip line 3
 	for scan.Scan() {
 		line := scan.Text()
 		if verbose > 2 {
-			fmt.Println(">>", line)
+			fmt.Fprintln(log, ">>", line)
 		}
 		if line == "S" {
 			parser = SUPPLY
@@ -50,75 +72,95 @@ func LoadGraph(path string, verbose int) (*Graph, error) {
 		n := strings.Fields(line)
 		if parser == EDGES {
 			if len(n) < 4 {
-				fmt.Println("Ignoring line, no edge:", line)
+				fmt.Fprintln(log, "Ignoring line, no edge:", line)
 				continue
 			}
 			i, err := strconv.ParseInt(n[0], 10, 0)
 			if err != nil {
-				fmt.Println("Error parsing edge source:", err)
+				fmt.Fprintln(log, "Error parsing edge source:", err)
 				continue
 			}
 			j, err := strconv.ParseInt(n[1], 10, 0)
 			if err != nil {
-				fmt.Println("Error parsing edge sink:", err)
+				fmt.Fprintln(log, "Error parsing edge sink:", err)
 				continue
 			}
 			v, err := strconv.ParseFloat(n[2], 64)
 			if err != nil {
-				fmt.Println("Error parsing edge variable cost:", err)
+				fmt.Fprintln(log, "Error parsing edge variable cost:", err)
 				continue
 			}
 			f, err := strconv.ParseFloat(n[3], 64)
 			if err != nil {
-				fmt.Println("Error parsing edge fixed cost:", err)
+				fmt.Fprintln(log, "Error parsing edge fixed cost:", err)
 				continue
 			}
+			lower, upper := 0., 0.
+			if len(n) >= 6 {
+				lower, err = strconv.ParseFloat(n[4], 64)
+				if err != nil {
+					fmt.Fprintln(log, "Error parsing edge lower bound:", err)
+					continue
+				}
+				upper, err = strconv.ParseFloat(n[5], 64)
+				if err != nil {
+					fmt.Fprintln(log, "Error parsing edge upper bound:", err)
+					continue
+				}
+			}
 			e := g.NewEdge(int(i), int(j), v, f)
+			g.bounds(int(i), int(j), lower, upper)
 			if verbose > 1 {
-				fmt.Println("New Edge:", e)
+				fmt.Fprintln(log, "New Edge:", e)
 			}
 		} else if parser == SUPPLY {
 			if len(n) < 2 {
-				fmt.Println("Ignoring line, no supply:", line)
+				fmt.Fprintln(log, "Ignoring line, no supply:", line)
 				continue
 			}
 			i, err := strconv.ParseInt(n[0], 10, 0)
 			if err != nil {
-				fmt.Println("Error parsing source:", err)
+				fmt.Fprintln(log, "Error parsing source:", err)
 				continue
 			}
 			s, err := strconv.ParseFloat(n[1], 64)
 			if err != nil {
-				fmt.Println("Error parsing supply value:", err)
+				fmt.Fprintln(log, "Error parsing supply value:", err)
 				continue
 			}
 			v := g.SourceSize(int(i), s)
 			if verbose > 1 {
-				fmt.Println("Supply:", v)
+				fmt.Fprintln(log, "Supply:", v)
 			}
 		} else if parser == DEMAND {
 			if len(n) < 2 {
-				fmt.Println("Ignoring line, no demand:", line)
+				fmt.Fprintln(log, "Ignoring line, no demand:", line)
 				continue
 			}
 			j, err := strconv.ParseInt(n[0], 10, 0)
 			if err != nil {
-				fmt.Println("Error parsing sink:", err)
+				fmt.Fprintln(log, "Error parsing sink:", err)
 				continue
 			}
 			s, err := strconv.ParseFloat(n[1], 64)
 			if err != nil {
-				fmt.Println("Error parsing demand value:", err)
+				fmt.Fprintln(log, "Error parsing demand value:", err)
 				continue
 			}
 			v := g.SinkSize(int(j), s)
 			if verbose > 1 {
-				fmt.Println("Demand:", v)
+				fmt.Fprintln(log, "Demand:", v)
 			}
 		} else {
-			fmt.Println("Ignoring line, unknown type:", line)
+			fmt.Fprintln(log, "Ignoring line, unknown type:", line)
 		}
 	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+	if g.Size() == 0 {
+		return nil, errors.New("No edges found")
+	}
 	return g, nil
 }
 
@@ -141,60 +183,171 @@ func (d *StaticLoader) Instance(name string) *Graph {
 	return nil
 }
 
+// Instances are read from name.DAT or gzip compressed name.DAT.gz files at
+// the root of a file system (directory, archive or bundle).
 type FileLoader struct {
 	dataPath string
-	data     map[string]*Graph
+	fsys     fs.FS
 	verbose  int
+	cache    *Cache
+	log      io.Writer
 }
 
 func NewFileLoader(dataPath string, verbose int) *FileLoader {
-	return &FileLoader{dataPath, make(map[string]*Graph), verbose}
+	return NewCachedFileLoader(dataPath, verbose, 0)
+}
+
+// Capacity bounds the number of instances kept in memory (<= 0 unbounded).
+func NewCachedFileLoader(dataPath string, verbose int, capacity int) *FileLoader {
+	return NewFSLoader(os.DirFS(dataPath), dataPath, verbose, capacity)
+}
+
+func NewFSLoader(fsys fs.FS, dataPath string, verbose int, capacity int) *FileLoader {
+	return &FileLoader{dataPath, fsys, verbose, NewCache(capacity), os.Stdout}
+}
+
+// WithLog returns a loader sharing the instances (cache) of d, writing its
+// messages to w (e.g. the log of one game).
+func (d *FileLoader) WithLog(w io.Writer) *FileLoader {
+	l := *d
+	l.log = w
+	return &l
+}
+
+// Files changed on disk (modification time or size) are reloaded.
+type fileVersion struct {
+	modTime int64
+	size    int64
 }
 
+// Safe for concurrent handlers, each instance is loaded once.
 func (d *FileLoader) Instance(name string) *Graph {
-	if g, ok := d.data[name]; ok {
-		return g
-	}
-	fmt.Print("Loading ", name, "... ")
-	g, err := LoadGraph(d.dataPath+"/"+name+".DAT", d.verbose)
+	g, err := d.load(name)
 	if err != nil {
-		fmt.Println("Error:", err)
+		fmt.Fprintln(d.log, "Error loading", name, err)
 		return nil
 	}
-	d.data[name] = g
-	fmt.Println(g)
 	return g
 }
 
-func (d *FileLoader) LoadAll() {
-	folder, err := os.Open(d.dataPath)
-	if err != nil {
-		fmt.Println("Error opening folder:", d.dataPath, err)
-		return
+func (d *FileLoader) file(name string) (string, fs.FileInfo, error) {
+	file := name + ".DAT"
+	info, err := fs.Stat(d.fsys, file)
+	if errors.Is(err, fs.ErrNotExist) {
+		file += ".gz"
+		info, err = fs.Stat(d.fsys, file)
 	}
-	defer folder.Close()
+	return file, info, err
+}
 
-	files, err := folder.Readdirnames(0)
+func (d *FileLoader) read(file string) ([]byte, error) {
+	data, err := fs.ReadFile(d.fsys, file)
+	if err != nil || !strings.HasSuffix(file, ".gz") {
+		return data, err
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
 	if err != nil {
-		fmt.Println("Error listing data files from:", d.dataPath, err)
-		return
+		return nil, err
 	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
 
-	for _, file := range files {
-		if !strings.HasSuffix(file, ".DAT") {
-			fmt.Println("Ignoring", file)
-			continue
+func (d *FileLoader) load(name string) (*Graph, error) {
+	file, info, err := d.file(name)
+	if err != nil {
+		return nil, err
+	}
+	version := fileVersion{info.ModTime().UnixNano(), info.Size()}
+	return d.cache.Get(name, version, func() (*Graph, error) {
+		data, err := d.read(file)
+		if err != nil {
+			return nil, err
 		}
-		fmt.Print("Loading ", file, "... ")
-		g, err := LoadGraph(d.dataPath+"/"+file, d.verbose)
+		g, err := readGraph(bytes.NewReader(data), d.verbose, d.log)
 		if err != nil {
-			fmt.Println("Error:", err)
+			return nil, err
+		}
+		fmt.Fprintln(d.log, "Loading", name+"...", g)
+		return g, nil
+	})
+}
+
+func (d *FileLoader) Invalidate(name string) {
+	d.cache.Invalidate(name)
+}
+
+func (d *FileLoader) InvalidateAll() {
+	d.cache.InvalidateAll()
+}
+
+func (d *FileLoader) Stats() CacheStats {
+	return d.cache.Stats()
+}
+
+// Instances are loaded in parallel by the given number of workers, the
+// error lists the instances that failed to load or are not feasible.
+func (d *FileLoader) LoadAll(threads int) (*Manifest, error) {
+	files, err := fs.ReadDir(d.fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("Error listing data files from %s: %s", d.dataPath, err)
+	}
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), ".gz")
+		if file.IsDir() || !strings.HasSuffix(name, ".DAT") {
+			fmt.Fprintln(d.log, "Ignoring", file.Name())
 			continue
 		}
-		name := strings.TrimSuffix(file, ".DAT")
-		d.data[name] = g
-		fmt.Println(g)
+		names = append(names, strings.TrimSuffix(name, ".DAT"))
 	}
 
-	fmt.Println("Total:", len(d.data))
+	m := loadAll(names, threads, d.info)
+
+	fmt.Fprintln(d.log, "Total:", len(m.Instances))
+	return m, m.Err()
+}
+
+func (d *FileLoader) info(name string) *InstanceInfo {
+	file, info, err := d.file(name)
+	if err != nil {
+		return &InstanceInfo{Name: name, Err: err}
+	}
+	data, err := d.read(file)
+	if err != nil {
+		return &InstanceInfo{Name: name, Err: err}
+	}
+	version := fileVersion{info.ModTime().UnixNano(), info.Size()}
+	g, err := d.cache.Get(name, version, func() (*Graph, error) {
+		return readGraph(bytes.NewReader(data), d.verbose, d.log)
+	})
+	if err != nil {
+		return &InstanceInfo{Name: name, Checksum: Checksum(data), Err: err}
+	}
+	return NewInstanceInfo(name, g, data)
+}
+
+func loadAll(names []string, threads int, load func(name string) *InstanceInfo) *Manifest {
+	if threads < 1 {
+		threads = 1
+	}
+	result := make([]*InstanceInfo, len(names))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for k := 0; k < threads; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result[i] = load(names[i])
+			}
+		}()
+	}
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return NewManifest(result)
 }
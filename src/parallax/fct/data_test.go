@@ -1,6 +1,9 @@
 package fct
 
 import (
+	"bytes"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -19,3 +22,61 @@ func TestLoadData(t *testing.T) {
 		t.Error("Wrong number of arks (100):", n)
 	}
 }
+
+func TestLoadAll(t *testing.T) {
+	dir := t.TempDir()
+	data, err := os.ReadFile("N104.DAT")
+	if err != nil {
+		t.Fatal("Error reading FCT data:", err)
+	}
+	os.WriteFile(dir+"/N104.DAT", data, 0644)
+	os.WriteFile(dir+"/EMPTY.DAT", []byte("\n\n\nS\nD\nEND\n"), 0644)
+	os.WriteFile(dir+"/README", []byte("ignored"), 0644)
+
+	m, err := NewFileLoader(dir, 0).LoadAll(2)
+	if err == nil {
+		t.Error("Missing error for EMPTY instance")
+	}
+	if n := len(m.Instances); n != 2 {
+		t.Fatal("Wrong number of instances (2):", n)
+	}
+	if f := m.Failed(); len(f) != 1 || f[0].Name != "EMPTY" {
+		t.Error("Wrong failed instances (EMPTY):", f)
+	}
+	i := m.Instances[1]
+	if i.Name != "N104" || i.Sources != 10 || i.Sinks != 10 || i.Arcs != 100 {
+		t.Error("Wrong instance summary:", i)
+	}
+	if !i.Balanced || i.Supply != 10000. || i.Err != nil {
+		t.Error("Instance not balanced (10000):", i)
+	}
+	if i.Checksum != Checksum(data) {
+		t.Error("Wrong checksum:", i.Checksum)
+	}
+}
+
+func TestLoaderLog(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(dir+"/T1.DAT", []byte("\n\n\n1 2 1. 0.\nX\nS\n1 10.\nD\n2 10.\n"), 0644)
+	d := NewFileLoader(dir, 0)
+	var log bytes.Buffer
+	l := d.WithLog(&log)
+	if l.Instance("T1") == nil || l.Instance("T2") != nil {
+		t.Fatal("Wrong instances loaded")
+	}
+	out := log.String()
+	for _, line := range []string{"Ignoring line, no edge: X", "Loading T1...", "Error loading T2"} {
+		if !strings.Contains(out, line) {
+			t.Error("Missing log message:", line, out)
+		}
+	}
+	if d.Instance("T1") != l.Instance("T1") || d.Stats().Loads != 1 {
+		t.Error("Instances not shared:", d.Stats())
+	}
+
+	log.Reset()
+	NewFileLoader(dir, 0).WithLog(&log).LoadAll(1)
+	if out := log.String(); !strings.Contains(out, "Ignoring line, no edge: X") || !strings.Contains(out, "Total: 1") {
+		t.Error("Missing log messages loading all:", out)
+	}
+}
@@ -0,0 +1,70 @@
+package fct
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// GraphViz export - sources and sinks in two ranks, edges labelled with
+// (variable, fixed) costs. Highlighted edges are drawn on top of the instance.
+
+type DotEdge struct {
+	Width float64 // penwidth
+	Color string
+	Label string
+}
+
+// WriteDot writes the graph in DOT format, highlight may be nil.
+func (g *Graph) WriteDot(w io.Writer, name string, highlight map[Key]*DotEdge) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "digraph %q {\n", name)
+	fmt.Fprintln(&buf, "\trankdir=LR;")
+	fmt.Fprintln(&buf, "\tnode [shape=circle];")
+
+	fmt.Fprintln(&buf, "\tsubgraph sources {")
+	fmt.Fprintln(&buf, "\t\trank=same;")
+	for _, id := range vertexIds(g.Sources) {
+		fmt.Fprintf(&buf, "\t\ts%d [label=\"%d\\n%.2f\"];\n", id, id, g.Sources[id].Data.Size)
+	}
+	fmt.Fprintln(&buf, "\t}")
+
+	fmt.Fprintln(&buf, "\tsubgraph sinks {")
+	fmt.Fprintln(&buf, "\t\trank=same;")
+	for _, id := range vertexIds(g.Sinks) {
+		fmt.Fprintf(&buf, "\t\tt%d [label=\"%d\\n%.2f\", shape=doublecircle];\n", id, id, g.Sinks[id].Data.Size)
+	}
+	fmt.Fprintln(&buf, "\t}")
+
+	for _, e := range g.Edges {
+		if !g.Enabled(e) {
+			continue
+		}
+		source, sink := e.I.Data.Id, e.J.Data.Id
+		label := fmt.Sprintf("%.2f, %.2f", g.Cost(e), e.Data.FCost)
+		if h, found := highlight[Key{source, sink}]; found {
+			if h.Label != "" {
+				label = h.Label
+			}
+			fmt.Fprintf(&buf, "\ts%d -> t%d [label=%q, penwidth=%.2f, color=%q, fontcolor=%q];\n", source, sink, label, h.Width, h.Color, h.Color)
+		} else if highlight != nil {
+			fmt.Fprintf(&buf, "\ts%d -> t%d [label=%q, color=gray, fontcolor=gray, style=dashed];\n", source, sink, label)
+		} else {
+			fmt.Fprintf(&buf, "\ts%d -> t%d [label=%q];\n", source, sink, label)
+		}
+	}
+	fmt.Fprintln(&buf, "}")
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func vertexIds(m map[int]*Vertex) []int {
+	result := make([]int, 0, len(m))
+	for id := range m {
+		result = append(result, id)
+	}
+	sort.Ints(result)
+	return result
+}
@@ -0,0 +1,64 @@
+package fct
+
+// Instance editing - removals change the shared topology, overlays of the
+// graph (or the graph's base) see them (their changes are kept by edge).
+
+// RemoveEdge returns the removed edge, nil if not found.
+func (g *Graph) RemoveEdge(source, sink int) *Edge {
+	e := g.Arc(source, sink)
+	if e == nil {
+		return nil
+	}
+	g.deleteArc(source, sink)
+	g.Typed.RemoveEdge(e)
+	return e
+}
+
+// RemoveSource removes the vertex and its arcs, nil if not found.
+func (g *Graph) RemoveSource(id int) *Vertex {
+	v, found := g.Sources[id]
+	if !found {
+		return nil
+	}
+	for _, e := range v.EdgeOut {
+		g.deleteArc(id, e.J.Data.Id)
+	}
+	delete(g.Sources, id)
+	g.RemoveVertex(v)
+	return v
+}
+
+func (g *Graph) RemoveSink(id int) *Vertex {
+	v, found := g.Sinks[id]
+	if !found {
+		return nil
+	}
+	for _, e := range v.EdgeIn {
+		g.deleteArc(e.I.Data.Id, id)
+	}
+	delete(g.Sinks, id)
+	g.RemoveVertex(v)
+	return v
+}
+
+func (g *Graph) deleteArc(source, sink int) {
+	delete(g.EdgeMap, EdgeKey(source, sink))
+	delete(g.arcs, Key{source, sink})
+}
+
+// Subgraph has the given edges (overlay costs applied) and their sources and
+// sinks with the original sizes, keys not found or disabled are ignored.
+func (g *Graph) Subgraph(keys []Key) *Graph {
+	result := NewGraph()
+	for _, key := range keys {
+		e := g.Arc(key.Source, key.Sink)
+		if e == nil || !g.Enabled(e) || result.Arc(key.Source, key.Sink) != nil {
+			continue
+		}
+		result.SourceSize(key.Source, e.I.Data.Size)
+		result.SinkSize(key.Sink, e.J.Data.Size)
+		result.NewEdge(key.Source, key.Sink, g.Cost(e), e.Data.FCost)
+		result.SetBounds(key.Source, key.Sink, e.Data.Lower, e.Data.Upper)
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package fct
+
+import "testing"
+
+func TestRemoveEdge(t *testing.T) {
+	g := testGraph(3)
+	if g.RemoveEdge(1, 4) == nil || g.RemoveEdge(1, 4) != nil {
+		t.Fatal("Error removing edge: 1:4")
+	}
+	if n := g.Size(); n != 8 || len(g.EdgeMap) != 8 {
+		t.Error("Wrong number of edges (8):", n, len(g.EdgeMap))
+	}
+	for i, e := range g.Edges {
+		if e.Index != i || g.Arc(e.I.Data.Id, e.J.Data.Id) != e {
+			t.Error("Wrong edge after removal:", i, e.Data)
+		}
+	}
+	if d := g.Sources[1].OutDegree(); d != 2 {
+		t.Error("Wrong source degree (2):", d)
+	}
+}
+
+func TestRemoveVertex(t *testing.T) {
+	g := testGraph(3)
+	if g.RemoveSource(2) == nil || g.RemoveSink(6) == nil || g.RemoveSink(6) != nil {
+		t.Fatal("Error removing vertices: 2, 6")
+	}
+	if n, m := g.Order(), g.Size(); n != 4 || m != 4 || len(g.EdgeMap) != 4 {
+		t.Error("Wrong graph after removal (4, 4):", n, m, len(g.EdgeMap))
+	}
+	if g.Arc(2, 4) != nil || g.Arc(1, 6) != nil || g.Arc(3, 5) == nil {
+		t.Error("Wrong edges after removal:", g.EdgeMap)
+	}
+	for i, v := range g.Vertices {
+		if v.Index != i {
+			t.Error("Wrong vertex index:", i, v.Index)
+		}
+	}
+}
+
+func TestRemoveOverlay(t *testing.T) {
+	g := testGraph(3)
+	o := g.Overlay()
+	o.SetCost(2, 5, 0.5)
+	o.Disable(3, 6)
+	o.SetCost(1, 5, 0.25)
+	g.RemoveEdge(1, 4)
+	o.RemoveEdge(1, 5)
+	if v := o.Cost(o.Arc(2, 5)); v != 0.5 {
+		t.Error("Overlay cost moved after removal (0.5):", v)
+	}
+	if o.Enabled(o.Arc(3, 6)) || !o.Enabled(o.Arc(3, 5)) {
+		t.Error("Overlay disabled edge moved after removal")
+	}
+	for _, e := range o.Edges {
+		if k := (Key{e.I.Data.Id, e.J.Data.Id}); k != (Key{2, 5}) && o.Cost(e) != e.Data.VCost {
+			t.Error("Wrong overlay cost after removal:", k, o.Cost(e))
+		}
+	}
+	if n := o.Changes(); n != 2 {
+		t.Error("Wrong number of changes (2):", n)
+	}
+}
+
+func TestSubgraph(t *testing.T) {
+	g := testGraph(3)
+	g.SourceSize(1, 10.)
+	o := g.Overlay()
+	o.SetCost(1, 4, 0.5)
+	o.Disable(2, 5)
+	s := o.Subgraph([]Key{{1, 4}, {1, 5}, {2, 5}, {9, 9}})
+	if n, m := s.Order(), s.Size(); n != 3 || m != 2 {
+		t.Error("Wrong subgraph (3, 2):", n, m)
+	}
+	if v := s.Arc(1, 4).Data.VCost; v != 0.5 {
+		t.Error("Wrong subgraph cost (0.5):", v)
+	}
+	if v := s.Sources[1].Data.Size; v != 10. {
+		t.Error("Wrong subgraph supply (10):", v)
+	}
+}
@@ -0,0 +1,52 @@
+package fct
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"math"
+	"sort"
+)
+
+// Fingerprint hashes what a solver sees: sources and sinks with their sizes
+// and the enabled edges with overlay costs and bounds. Equal graphs (in any
+// edge order) have equal fingerprints.
+func (g *Graph) Fingerprint() string {
+	h := sha256.New()
+	buf := make([]byte, 8)
+	number := func(h hash.Hash, v float64) {
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(v))
+		h.Write(buf)
+	}
+	id := func(h hash.Hash, v int) {
+		binary.LittleEndian.PutUint64(buf, uint64(v))
+		h.Write(buf)
+	}
+
+	for _, m := range []map[int]*Vertex{g.Sources, g.Sinks} {
+		id(h, len(m))
+		for _, i := range vertexIds(m) {
+			id(h, i)
+			number(h, m[i].Data.Size)
+		}
+	}
+
+	edges := g.edges()
+	keys := make([]Key, len(edges))
+	for i, e := range edges {
+		keys[i] = Key{e.I.Data.Id, e.J.Data.Id}
+	}
+	sort.Sort(KeySort(keys))
+	id(h, len(keys))
+	for _, key := range keys {
+		e := g.Arc(key.Source, key.Sink)
+		id(h, key.Source)
+		id(h, key.Sink)
+		number(h, g.Cost(e))
+		number(h, e.Data.FCost)
+		number(h, e.Data.Lower)
+		number(h, g.Capacity(e))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,142 @@
+package fct
+
+import (
+	"fmt"
+	"math"
+	"parallax/graph"
+	"sort"
+)
+
+// Feasibility - maximum flow from supply to demand over a subset of edges
+
+type MaxFlow struct {
+	Supply, Demand, Value float64
+	Feasible              bool
+	Flow                  map[Key]float64
+	// Minimum cut: supply and demand arcs and instance edges blocking the rest
+	CutSources, CutSinks []int
+	CutEdges             []Key
+	// Supply not shipped and demand not met, by vertex id (negative when the
+	// lower bounds exceed the supply or demand)
+	Sources, Sinks map[int]float64
+}
+
+func (f *MaxFlow) String() string {
+	return fmt.Sprintf("Max Flow %.2f (Supply %.2f, Demand %.2f), Feasible %t", f.Value, f.Supply, f.Demand, f.Feasible)
+}
+
+// Edge capacity is min(supply, demand, upper), nil keys means all (enabled)
+// edges. Lower bounds are shipped up front, the flow on the network is the rest.
+func (g *Graph) MaxFlow(keys []Key) *MaxFlow {
+	selected := make([]*Edge, 0)
+	if keys == nil {
+		selected = append(selected, g.Edges...)
+	} else {
+		seen := make(map[Key]bool)
+		for _, key := range keys {
+			if e := g.Arc(key.Source, key.Sink); e != nil && !seen[key] {
+				seen[key] = true
+				selected = append(selected, e)
+			}
+		}
+	}
+	lower := 0.
+	lowerOut := make(map[int]float64)
+	lowerIn := make(map[int]float64)
+	enabled := selected[:0]
+	for _, e := range selected {
+		if !g.Enabled(e) {
+			continue
+		}
+		enabled = append(enabled, e)
+		l := e.Data.Lower
+		lower += l
+		lowerOut[e.I.Data.Id] += l
+		lowerIn[e.J.Data.Id] += l
+	}
+
+	n := graph.New()
+	s, t := n.Vertex(), n.Vertex()
+	capacity := make([]float64, 0)
+	arc := func(i, j *graph.Vertex, c float64) *graph.Edge {
+		e := n.Connect(i).To(j)
+		capacity = append(capacity, math.Max(c, 0.))
+		return e
+	}
+
+	sources := make(map[int]*graph.Vertex)
+	supply := make(map[int]*graph.Edge)
+	for id, v := range g.Sources {
+		sources[id] = n.Vertex()
+		supply[id] = arc(s, sources[id], v.Data.Size-lowerOut[id])
+	}
+	sinks := make(map[int]*graph.Vertex)
+	demand := make(map[int]*graph.Edge)
+	for id, v := range g.Sinks {
+		sinks[id] = n.Vertex()
+		demand[id] = arc(sinks[id], t, v.Data.Size-lowerIn[id])
+	}
+
+	edges := make(map[*Edge]*graph.Edge)
+	for _, e := range enabled {
+		edges[e] = arc(sources[e.I.Data.Id], sinks[e.J.Data.Id], g.Capacity(e)-e.Data.Lower)
+	}
+
+	flow := graph.Dinic(n, s, t, func(e *graph.Edge) float64 {
+		return capacity[e.Index]
+	})
+
+	result := &MaxFlow{
+		Supply:     g.Supply(),
+		Demand:     g.Demand(),
+		Value:      flow.Value + lower,
+		Flow:       make(map[Key]float64),
+		CutSources: make([]int, 0),
+		CutSinks:   make([]int, 0),
+		CutEdges:   make([]Key, 0),
+		Sources:    make(map[int]float64),
+		Sinks:      make(map[int]float64),
+	}
+	result.Feasible = result.Value > math.Max(result.Supply, result.Demand)-0.001
+
+	for _e, e := range edges {
+		key := Key{_e.I.Data.Id, _e.J.Data.Id}
+		if _e.Data.Lower > g.Capacity(_e)+0.001 {
+			result.Feasible = false
+		}
+		if m := _e.Data.Lower + flow.Flow[e.Index]; m > 0.001 {
+			result.Flow[key] = m
+		}
+		if flow.Cut[e.I.Index] && !flow.Cut[e.J.Index] {
+			result.CutEdges = append(result.CutEdges, key)
+		}
+	}
+	for id, e := range supply {
+		if flow.Cut[e.I.Index] && !flow.Cut[e.J.Index] {
+			result.CutSources = append(result.CutSources, id)
+		}
+		left := g.Sources[id].Data.Size - lowerOut[id] - flow.Flow[e.Index]
+		if math.Abs(left) > 0.001 {
+			result.Sources[id] = left
+		}
+		if left < -0.001 {
+			result.Feasible = false
+		}
+	}
+	for id, e := range demand {
+		if flow.Cut[e.I.Index] && !flow.Cut[e.J.Index] {
+			result.CutSinks = append(result.CutSinks, id)
+		}
+		left := g.Sinks[id].Data.Size - lowerIn[id] - flow.Flow[e.Index]
+		if math.Abs(left) > 0.001 {
+			result.Sinks[id] = left
+		}
+		if left < -0.001 {
+			result.Feasible = false
+		}
+	}
+	sort.Ints(result.CutSources)
+	sort.Ints(result.CutSinks)
+	sort.Sort(KeySort(result.CutEdges))
+	return result
+}
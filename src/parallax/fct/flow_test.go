@@ -0,0 +1,102 @@
+package fct
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaxFlow(t *testing.T) {
+	g, err := LoadGraph("N104.DAT", 0)
+	if err != nil {
+		t.Fatal("Error loading FCT data:", err)
+	}
+	f := g.MaxFlow(nil)
+	if !f.Feasible || f.Value < 9999.999 {
+		t.Error("Instance not feasible:", f)
+	}
+
+	// source 1 (615) only to sink 11 (159)
+	keys := []Key{{1, 11}}
+	f = g.MaxFlow(keys)
+	if f.Feasible || f.Value != 159. {
+		t.Error("Wrong max flow (159):", f)
+	}
+	if len(f.CutSources)+len(f.CutSinks)+len(f.CutEdges) != 1 {
+		t.Error("Wrong min cut:", f.CutSources, f.CutSinks, f.CutEdges)
+	}
+	if f.Sources[1] != 615.-159. || f.Sinks[11] != 0 {
+		t.Error("Wrong shortfall:", f.Sources, f.Sinks)
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	g := NewGraph()
+	g.SourceSize(1, 10.)
+	g.SourceSize(2, 5.)
+	g.SinkSize(3, 10.)
+	g.NewEdge(1, 3, 1., 1.)
+	g.NewEdge(2, 3, 1., 1.)
+	err := g.Diagnose()
+	e, ok := err.(*Infeasible)
+	if !ok || e.Value != 10. || e.Sources[1]+e.Sources[2] != 5. {
+		t.Fatal("Wrong diagnosis:", err)
+	}
+
+	b, dummy := g.Balance(100.)
+	if dummy == nil || b.Sinks[4] != dummy || dummy.Data.Size != 5. || b.Size() != 4 {
+		t.Fatal("Wrong dummy sink:", b, dummy)
+	}
+	if err := b.Diagnose(); err != nil {
+		t.Error("Balanced instance not feasible:", err)
+	}
+	if _, dummy := b.Balance(100.); dummy != nil {
+		t.Error("Balanced instance with dummy:", dummy)
+	}
+
+	// sink 4 without arcs
+	g.SourceSize(2, 10.)
+	g.SinkSize(4, 10.)
+	if err := g.Diagnose(); err == nil || !strings.HasPrefix(err.Error(), "Infeasible, max flow 10.00 of 20.00") || !strings.HasSuffix(err.Error(), "sinks 4 (10.00)") {
+		t.Error("Wrong diagnosis:", err)
+	}
+}
+
+func TestBounds(t *testing.T) {
+	g, err := LoadGraph("N104.DAT", 0)
+	if err != nil {
+		t.Fatal("Error loading FCT data:", err)
+	}
+	if e := g.Arc(1, 16); e.Data.Lower != 0. || e.Data.Upper != 615. {
+		t.Error("Wrong bounds (0, 615):", e.Data.Lower, e.Data.Upper)
+	}
+
+	g = NewGraph()
+	g.SourceSize(1, 10.)
+	g.SinkSize(2, 5.)
+	g.SinkSize(3, 5.)
+	g.NewEdge(1, 2, 1., 1.)
+	g.NewEdge(1, 3, 1., 1.)
+	g.SetBounds(1, 2, 2., 4.)
+	f := g.MaxFlow(nil)
+	if f.Feasible || f.Value != 9. || f.Flow[Key{1, 2}] != 4. || f.Sinks[2] != 1. {
+		t.Error("Wrong capacitated max flow (9):", f, f.Flow, f.Sinks)
+	}
+	if c := g.Capacity(g.Arc(1, 2)); c != 4. {
+		t.Error("Wrong capacity (4):", c)
+	}
+
+	// lower bound over the demand
+	g.SetBounds(1, 2, 6., 8.)
+	if f := g.MaxFlow(nil); f.Feasible || f.Sinks[2] != -1. {
+		t.Error("Wrong lower bound shortfall (-1):", f, f.Sinks)
+	}
+
+	g.SetBounds(1, 2, 2., 5.)
+	if f := g.MaxFlow(nil); !f.Feasible || f.Flow[Key{1, 2}] != 5. {
+		t.Error("Wrong feasible flow:", f, f.Flow)
+	}
+	c := g.Overlay().Clone()
+	if e := c.Arc(1, 2); e.Data.Lower != 2. || e.Data.Upper != 5. {
+		t.Error("Wrong clone bounds (2, 5):", e.Data.Lower, e.Data.Upper)
+	}
+}
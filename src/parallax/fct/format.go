@@ -0,0 +1,386 @@
+package fct
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Instance formats - FCTP (.DAT), JSON, CSV (arcs, supply and demand files)
+// and DIMACS min-cost-flow. Writers use the overlay costs and skip disabled
+// edges; lower bounds are kept and upper bounds are written as the capacity,
+// min(supply, demand, upper).
+
+func (g *Graph) edges() []*Edge {
+	result := make([]*Edge, 0, g.Size())
+	for _, e := range g.Edges {
+		if g.Enabled(e) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// FCTP numbers are written with a trailing dot when integral (e.g. 190.)
+func fctpNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += "."
+	}
+	return s
+}
+
+// WriteFile creates the file name (truncated if it exists) and fills it with write.
+func WriteFile(name string, write func(w io.Writer) error) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func WriteGraph(w io.Writer, g *Graph, name string) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "BEGIN FCTP PROBLEM.    %-14s\n", name)
+	fmt.Fprintf(b, " %-14s SOURCES=%5d , SINKS=%5d & MAX OPTOFV=UNKNOWN\n", name, g.SourceOrder(), g.SinkOrder())
+	fmt.Fprintln(b, "ARCS")
+	for _, e := range g.edges() {
+		fmt.Fprintf(b, "%9d%17d%18s%10s%10s%10s    -1.     1. F\n",
+			e.I.Data.Id, e.J.Data.Id, fctpNumber(g.Cost(e)), fctpNumber(e.Data.FCost), fctpNumber(e.Data.Lower), fctpNumber(g.Capacity(e)))
+	}
+	fmt.Fprintln(b, "S")
+	for _, id := range vertexIds(g.Sources) {
+		fmt.Fprintf(b, "%9d%19s\n", id, fctpNumber(g.Sources[id].Data.Size))
+	}
+	fmt.Fprintln(b, "D")
+	for _, id := range vertexIds(g.Sinks) {
+		fmt.Fprintf(b, "%9d%19s\n", id, fctpNumber(g.Sinks[id].Data.Size))
+	}
+	fmt.Fprintln(b, "END")
+	return b.Flush()
+}
+
+// JSON
+
+type jsonVertex struct {
+	Id   int     `json:"id"`
+	Size float64 `json:"size"`
+}
+
+type jsonEdge struct {
+	Source   int     `json:"source"`
+	Sink     int     `json:"sink"`
+	VCost    float64 `json:"vcost"`
+	FCost    float64 `json:"fcost"`
+	Lower    float64 `json:"lower,omitempty"`
+	Capacity float64 `json:"capacity,omitempty"`
+}
+
+type jsonGraph struct {
+	Name    string       `json:"name,omitempty"`
+	Sources []jsonVertex `json:"sources"`
+	Sinks   []jsonVertex `json:"sinks"`
+	Edges   []jsonEdge   `json:"edges"`
+}
+
+func WriteJSON(w io.Writer, g *Graph, name string) error {
+	j := jsonGraph{name, make([]jsonVertex, 0), make([]jsonVertex, 0), make([]jsonEdge, 0)}
+	for _, id := range vertexIds(g.Sources) {
+		j.Sources = append(j.Sources, jsonVertex{id, g.Sources[id].Data.Size})
+	}
+	for _, id := range vertexIds(g.Sinks) {
+		j.Sinks = append(j.Sinks, jsonVertex{id, g.Sinks[id].Data.Size})
+	}
+	for _, e := range g.edges() {
+		j.Edges = append(j.Edges, jsonEdge{e.I.Data.Id, e.J.Data.Id, g.Cost(e), e.Data.FCost, e.Data.Lower, g.Capacity(e)})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(j)
+}
+
+func ReadJSON(r io.Reader) (*Graph, error) {
+	var j jsonGraph
+	if err := json.NewDecoder(r).Decode(&j); err != nil {
+		return nil, fmt.Errorf("Error parsing JSON: %s", err)
+	}
+	g := NewGraph()
+	for _, v := range j.Sources {
+		g.SourceSize(v.Id, v.Size)
+	}
+	for _, v := range j.Sinks {
+		g.SinkSize(v.Id, v.Size)
+	}
+	for _, e := range j.Edges {
+		g.NewEdge(e.Source, e.Sink, e.VCost, e.FCost)
+		g.bounds(e.Source, e.Sink, e.Lower, e.Capacity)
+	}
+	if g.Size() == 0 {
+		return nil, errors.New("No edges found")
+	}
+	return g, nil
+}
+
+// CSV - arcs (source, sink, vcost, fcost, lower, capacity), supply (source, size)
+// and demand (sink, size), each with a header line.
+
+func WriteCSV(arcs, supply, demand io.Writer, g *Graph) error {
+	write := func(w io.Writer, header []string, rows [][]string) error {
+		c := csv.NewWriter(w)
+		c.Write(header)
+		c.WriteAll(rows)
+		return c.Error()
+	}
+	number := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	vertices := func(m map[int]*Vertex) [][]string {
+		rows := make([][]string, 0, len(m))
+		for _, id := range vertexIds(m) {
+			rows = append(rows, []string{strconv.Itoa(id), number(m[id].Data.Size)})
+		}
+		return rows
+	}
+
+	rows := make([][]string, 0, g.Size())
+	for _, e := range g.edges() {
+		rows = append(rows, []string{
+			strconv.Itoa(e.I.Data.Id),
+			strconv.Itoa(e.J.Data.Id),
+			number(g.Cost(e)),
+			number(e.Data.FCost),
+			number(e.Data.Lower),
+			number(g.Capacity(e)),
+		})
+	}
+	if err := write(arcs, []string{"source", "sink", "vcost", "fcost", "lower", "capacity"}, rows); err != nil {
+		return err
+	}
+	if err := write(supply, []string{"source", "supply"}, vertices(g.Sources)); err != nil {
+		return err
+	}
+	return write(demand, []string{"sink", "demand"}, vertices(g.Sinks))
+}
+
+func ReadCSV(arcs, supply, demand io.Reader) (*Graph, error) {
+	read := func(r io.Reader, name string, columns int, row func(n []string) error) error {
+		c := csv.NewReader(r)
+		c.FieldsPerRecord = -1
+		records, err := c.ReadAll()
+		if err != nil {
+			return fmt.Errorf("Error parsing %s: %s", name, err)
+		}
+		for k, n := range records {
+			if k == 0 {
+				continue // header
+			}
+			if len(n) < columns {
+				return fmt.Errorf("Error parsing %s line %d: %d columns expected", name, k+1, columns)
+			}
+			if err := row(n); err != nil {
+				return fmt.Errorf("Error parsing %s line %d: %s", name, k+1, err)
+			}
+		}
+		return nil
+	}
+
+	g := NewGraph()
+	err := read(arcs, "arcs", 4, func(n []string) error {
+		columns := 6
+		if len(n) < columns {
+			columns = 4
+		}
+		ids, values, err := parseFields(n[:2], n[2:columns])
+		if err != nil {
+			return err
+		}
+		g.NewEdge(ids[0], ids[1], values[0], values[1])
+		if columns == 6 {
+			g.bounds(ids[0], ids[1], values[2], values[3])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	size := func(set func(id int, s float64) *Vertex) func(n []string) error {
+		return func(n []string) error {
+			ids, values, err := parseFields(n[:1], n[1:2])
+			if err != nil {
+				return err
+			}
+			set(ids[0], values[0])
+			return nil
+		}
+	}
+	if err := read(supply, "supply", 2, size(g.SourceSize)); err != nil {
+		return nil, err
+	}
+	if err := read(demand, "demand", 2, size(g.SinkSize)); err != nil {
+		return nil, err
+	}
+	if g.Size() == 0 {
+		return nil, errors.New("No edges found")
+	}
+	return g, nil
+}
+
+func parseFields(ints, floats []string) ([]int, []float64, error) {
+	ids := make([]int, len(ints))
+	for k, s := range ints {
+		i, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return nil, nil, err
+		}
+		ids[k] = i
+	}
+	values := make([]float64, len(floats))
+	for k, s := range floats {
+		v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, nil, err
+		}
+		values[k] = v
+	}
+	return ids, values, nil
+}
+
+// DIMACS min-cost-flow - nodes are numbered 1..n (sources first, then sinks,
+// ids kept in comments). Fixed costs have no DIMACS field, they are written
+// as "c fcost <tail> <head> <value>" comment lines and read back.
+
+func WriteDIMACS(w io.Writer, g *Graph, name string) error {
+	b := bufio.NewWriter(w)
+	sources, sinks := vertexIds(g.Sources), vertexIds(g.Sinks)
+	node := make(map[*Vertex]int)
+	edges := g.edges()
+
+	fmt.Fprintf(b, "c FCTP %s\n", name)
+	fmt.Fprintf(b, "p min %d %d\n", len(sources)+len(sinks), len(edges))
+	for _, id := range sources {
+		v := g.Sources[id]
+		node[v] = len(node) + 1
+		fmt.Fprintf(b, "c source %d %d\n", node[v], id)
+		fmt.Fprintf(b, "n %d %s\n", node[v], strconv.FormatFloat(v.Data.Size, 'f', -1, 64))
+	}
+	for _, id := range sinks {
+		v := g.Sinks[id]
+		node[v] = len(node) + 1
+		fmt.Fprintf(b, "c sink %d %d\n", node[v], id)
+		fmt.Fprintf(b, "n %d %s\n", node[v], strconv.FormatFloat(-v.Data.Size, 'f', -1, 64))
+	}
+	for _, e := range edges {
+		i, j := node[e.I], node[e.J]
+		fmt.Fprintf(b, "a %d %d %s %s %s\n", i, j, strconv.FormatFloat(e.Data.Lower, 'f', -1, 64),
+			strconv.FormatFloat(g.Capacity(e), 'f', -1, 64), strconv.FormatFloat(g.Cost(e), 'f', -1, 64))
+		fmt.Fprintf(b, "c fcost %d %d %s\n", i, j, strconv.FormatFloat(e.Data.FCost, 'f', -1, 64))
+	}
+	return b.Flush()
+}
+
+// Nodes with supply are sources, with demand sinks; nodes without size are
+// sources if they are the tail of an arc. Original ids come from the
+// "c source" and "c sink" comments when present.
+func ReadDIMACS(r io.Reader) (*Graph, error) {
+	type arc struct {
+		i, j                int
+		lower, upper, vcost float64
+	}
+	ids := make(map[int]int)
+	sizes := make(map[int]float64)
+	fcosts := make(map[[2]int]float64)
+	arcs := make([]arc, 0)
+	tails := make(map[int]bool)
+
+	scan := bufio.NewScanner(r)
+	line := 0
+	for scan.Scan() {
+		line++
+		n := strings.Fields(scan.Text())
+		if len(n) == 0 {
+			continue
+		}
+		var err error
+		switch {
+		case n[0] == "c" && len(n) == 4 && (n[1] == "source" || n[1] == "sink"):
+			var k []int
+			k, _, err = parseFields(n[2:4], nil)
+			if err == nil {
+				ids[k[0]] = k[1]
+			}
+		case n[0] == "c" && len(n) == 5 && n[1] == "fcost":
+			var k []int
+			var v []float64
+			k, v, err = parseFields(n[2:4], n[4:5])
+			if err == nil {
+				fcosts[[2]int{k[0], k[1]}] = v[0]
+			}
+		case n[0] == "c" || n[0] == "p":
+		case n[0] == "n" && len(n) >= 3:
+			var k []int
+			var v []float64
+			k, v, err = parseFields(n[1:2], n[2:3])
+			if err == nil {
+				sizes[k[0]] = v[0]
+			}
+		case n[0] == "a" && len(n) >= 6:
+			var k []int
+			var v []float64
+			k, v, err = parseFields(n[1:3], n[3:6])
+			if err == nil {
+				arcs = append(arcs, arc{k[0], k[1], v[0], v[1], v[2]})
+				tails[k[0]] = true
+			}
+		default:
+			err = errors.New("unknown line")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing DIMACS line %d: %s", line, err)
+		}
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+
+	id := func(node int) int {
+		if i, found := ids[node]; found {
+			return i
+		}
+		return node
+	}
+	g := NewGraph()
+	for node, s := range sizes {
+		if s > 0 || s == 0 && tails[node] {
+			g.SourceSize(id(node), s)
+		} else {
+			g.SinkSize(id(node), -s)
+		}
+	}
+	for _, a := range arcs {
+		g.NewEdge(id(a.i), id(a.j), a.vcost, fcosts[[2]int{a.i, a.j}])
+		g.bounds(id(a.i), id(a.j), a.lower, a.upper)
+	}
+	if g.Size() == 0 {
+		return nil, errors.New("No edges found")
+	}
+	return g, nil
+}
+
+// Capacity read as upper bound, none (+Inf) if not positive.
+func (g *Graph) bounds(source, sink int, lower, capacity float64) {
+	if capacity <= 0 {
+		capacity = math.Inf(1)
+	}
+	g.SetBounds(source, sink, lower, capacity)
+}
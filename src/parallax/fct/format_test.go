@@ -0,0 +1,88 @@
+package fct
+
+import (
+	"bytes"
+	"testing"
+)
+
+func sameGraph(t *testing.T, format string, a, b *Graph) {
+	if a.Size() != b.Size() || a.SourceOrder() != b.SourceOrder() || a.SinkOrder() != b.SinkOrder() {
+		t.Fatal("Wrong graph", format+":", b)
+	}
+	for key, e := range a.EdgeMap {
+		_e := b.EdgeMap[key]
+		if _e == nil || _e.Data.VCost != e.Data.VCost || _e.Data.FCost != e.Data.FCost ||
+			_e.Data.Lower != e.Data.Lower || b.Capacity(_e) != a.Capacity(e) {
+			t.Error("Wrong edge", format+":", key, _e)
+		}
+	}
+	for id, v := range a.Sources {
+		if _v := b.Sources[id]; _v == nil || _v.Data.Size != v.Data.Size {
+			t.Error("Wrong source", format+":", id)
+		}
+	}
+	for id, v := range a.Sinks {
+		if _v := b.Sinks[id]; _v == nil || _v.Data.Size != v.Data.Size {
+			t.Error("Wrong sink", format+":", id)
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	g, err := LoadGraph("N104.DAT", 0)
+	if err != nil {
+		t.Fatal("Error loading N104:", err)
+	}
+	g.SetBounds(1, 16, 10., 400.)
+
+	var fctp, js, dimacs, arcs, supply, demand bytes.Buffer
+	if err := WriteGraph(&fctp, g, "N104"); err != nil {
+		t.Fatal(err)
+	}
+	if _g, err := ReadGraph(&fctp, 0); err != nil {
+		t.Error("Error reading FCTP:", err)
+	} else {
+		sameGraph(t, "FCTP", g, _g)
+	}
+
+	if err := WriteJSON(&js, g, "N104"); err != nil {
+		t.Fatal(err)
+	}
+	if _g, err := ReadJSON(&js); err != nil {
+		t.Error("Error reading JSON:", err)
+	} else {
+		sameGraph(t, "JSON", g, _g)
+	}
+
+	if err := WriteCSV(&arcs, &supply, &demand, g); err != nil {
+		t.Fatal(err)
+	}
+	if _g, err := ReadCSV(&arcs, &supply, &demand); err != nil {
+		t.Error("Error reading CSV:", err)
+	} else {
+		sameGraph(t, "CSV", g, _g)
+	}
+
+	if err := WriteDIMACS(&dimacs, g, "N104"); err != nil {
+		t.Fatal(err)
+	}
+	if _g, err := ReadDIMACS(&dimacs); err != nil {
+		t.Error("Error reading DIMACS:", err)
+	} else {
+		sameGraph(t, "DIMACS", g, _g)
+	}
+}
+
+func TestReadDIMACS(t *testing.T) {
+	in := "p min 3 2\nn 1 5\nn 2 -3\nn 3 -2\na 1 2 0 5 1.5\na 1 3 0 5 2\n"
+	g, err := ReadDIMACS(bytes.NewBufferString(in))
+	if err != nil {
+		t.Fatal("Error reading DIMACS:", err)
+	}
+	if g.SourceOrder() != 1 || g.SinkOrder() != 2 || g.Arc(1, 2).Data.VCost != 1.5 || g.Sinks[3].Data.Size != 2 {
+		t.Error("Wrong DIMACS graph:", g)
+	}
+	if _, err := ReadDIMACS(bytes.NewBufferString("x 1\n")); err == nil {
+		t.Error("No error for unknown line")
+	}
+}
@@ -0,0 +1,105 @@
+package fct
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Instance Manifest - summary and integrity of loaded instances
+
+type InstanceInfo struct {
+	Name                 string
+	Sources, Sinks, Arcs int
+	Supply, Demand       float64
+	Balanced             bool
+	Checksum             string
+	Err                  error
+}
+
+func NewInstanceInfo(name string, g *Graph, data []byte) *InstanceInfo {
+	info := &InstanceInfo{
+		Name:     name,
+		Sources:  g.SourceOrder(),
+		Sinks:    g.SinkOrder(),
+		Arcs:     g.Size(),
+		Supply:   g.Supply(),
+		Demand:   g.Demand(),
+		Checksum: Checksum(data),
+	}
+	info.Balanced = math.Abs(info.Supply-info.Demand) < 0.001
+	info.Err = g.Check()
+	return info
+}
+
+func (i *InstanceInfo) String() string {
+	status := "OK"
+	if i.Err != nil {
+		status = i.Err.Error()
+	}
+	return fmt.Sprintf("%-12s %4d %4d %6d %12.2f %12.2f %-5t %.12s %s",
+		i.Name, i.Sources, i.Sinks, i.Arcs, i.Supply, i.Demand, i.Balanced, i.Checksum, status)
+}
+
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+type Manifest struct {
+	Instances []*InstanceInfo
+}
+
+// Instances are sorted by name.
+func NewManifest(instances []*InstanceInfo) *Manifest {
+	sort.Sort(infoSort(instances))
+	return &Manifest{instances}
+}
+
+func (m *Manifest) String() string {
+	out := fmt.Sprintf("%-12s %4s %4s %6s %12s %12s %-5s %-12s %s\n",
+		"Name", "S", "D", "Arcs", "Supply", "Demand", "Bal.", "Checksum", "Status")
+	for _, i := range m.Instances {
+		out += i.String() + "\n"
+	}
+	return out
+}
+
+func (m *Manifest) Failed() []*InstanceInfo {
+	result := make([]*InstanceInfo, 0)
+	for _, i := range m.Instances {
+		if i.Err != nil {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func (m *Manifest) Err() error {
+	failed := m.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	return &LoadError{failed}
+}
+
+type LoadError struct {
+	Failed []*InstanceInfo
+}
+
+func (e *LoadError) Error() string {
+	out := make([]string, len(e.Failed))
+	for i, info := range e.Failed {
+		out[i] = fmt.Sprint(info.Name, " (", info.Err, ")")
+	}
+	return fmt.Sprintf("%d instances failed: %s", len(e.Failed), strings.Join(out, ", "))
+}
+
+type infoSort []*InstanceInfo
+
+func (v infoSort) Len() int           { return len(v) }
+func (v infoSort) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
+func (v infoSort) Less(i, j int) bool { return v[i].Name < v[j].Name }
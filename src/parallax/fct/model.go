@@ -2,7 +2,9 @@ package fct
 
 import (
 	"fmt"
+	"math"
 	"parallax/graph"
+	"strconv"
 )
 
 // FCT data model
@@ -16,57 +18,101 @@ func (v *VertexData) String() string {
 	return fmt.Sprintf("[%d, %.2f]", v.Id, v.Size)
 }
 
+// Arc bounds: Lower <= flow <= Upper are hard bounds, Lower is always shipped
+// (default 0), Upper defaults to +Inf (capacity given by supply and demand).
 type EdgeData struct {
 	VCost, FCost float64
+	Lower, Upper float64
 }
 
 func (e *EdgeData) String() string {
 	return fmt.Sprintf("[%.2f, %.2f]", e.VCost, e.FCost)
 }
 
+// Edge weights for graph algorithms
+func VCost(e *Edge) float64 {
+	return e.Data.VCost
+}
+
+func FCost(e *Edge) float64 {
+	return e.Data.FCost
+}
+
 func EdgeKey(source, sink int) string {
-	return fmt.Sprint(source, ":", sink)
+	return strconv.Itoa(source) + ":" + strconv.Itoa(sink)
+}
+
+// Edge lookup key, comparable without string formatting
+type Key struct {
+	Source, Sink int
+}
+
+func (k Key) String() string {
+	return EdgeKey(k.Source, k.Sink)
+}
+
+type KeySort []Key
+
+func (v KeySort) Len() int      { return len(v) }
+func (v KeySort) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v KeySort) Less(i, j int) bool {
+	return v[i].Source < v[j].Source || v[i].Source == v[j].Source && v[i].Sink < v[j].Sink
 }
 
+type Vertex = graph.TypedVertex[*VertexData, *EdgeData]
+type Edge = graph.TypedEdge[*VertexData, *EdgeData]
+
 type Graph struct {
-	*graph.Graph
-	Sources, Sinks map[int]*graph.Vertex
-	EdgeMap        map[string]*graph.Edge
+	*graph.Typed[*VertexData, *EdgeData]
+	Sources, Sinks map[int]*Vertex
+	EdgeMap        map[string]*Edge // by EdgeKey, lookups use Arc
+	arcs           map[Key]*Edge
+	overlay        *overlay
 }
 
 func (g *Graph) String() string {
-	return fmt.Sprintf("Sources %d, Sinks %d, Edges %d", g.SourceOrder(), g.SinkOrder(), g.Graph.Size())
+	return fmt.Sprintf("Sources %d, Sinks %d, Edges %d", g.SourceOrder(), g.SinkOrder(), g.Size())
 }
 
 func NewGraph() *Graph {
 	return &Graph{
-		graph.New(),
-		make(map[int]*graph.Vertex),
-		make(map[int]*graph.Vertex),
-		make(map[string]*graph.Edge),
+		graph.NewTyped[*VertexData, *EdgeData](),
+		make(map[int]*Vertex),
+		make(map[int]*Vertex),
+		make(map[string]*Edge),
+		make(map[Key]*Edge),
+		nil,
 	}
 }
 
+// Clone is an independent copy, overlay costs are applied and disabled edges dropped.
 func (g *Graph) Clone() *Graph {
 	result := NewGraph()
+	result.Vertices = make([]*Vertex, 0, g.Order())
+	result.Edges = make([]*Edge, 0, g.Size())
+	result.EdgeMap = make(map[string]*Edge, g.Size())
+	result.arcs = make(map[Key]*Edge, g.Size())
 
 	for _, v := range g.Sources {
-		_v := v.Data.(*VertexData)
+		_v := v.Data
 		result.SourceSize(_v.Id, _v.Size)
 	}
 
 	for _, v := range g.Sinks {
-		_v := v.Data.(*VertexData)
+		_v := v.Data
 		result.SinkSize(_v.Id, _v.Size)
 	}
 
 	for _, e := range g.Edges {
-		source := e.I.Data.(*VertexData)
-		sink := e.J.Data.(*VertexData)
-		_e := e.Data.(*EdgeData)
-		vcost := _e.VCost
-		fcost := _e.FCost
-		result.NewEdge(source.Id, sink.Id, vcost, fcost)
+		if !g.Enabled(e) {
+			continue
+		}
+		source := e.I.Data
+		sink := e.J.Data
+		vcost := g.Cost(e)
+		_e := e.Data
+		result.NewEdge(source.Id, sink.Id, vcost, _e.FCost)
+		result.SetBounds(source.Id, sink.Id, _e.Lower, _e.Upper)
 	}
 
 	return result
@@ -80,7 +126,7 @@ func (g *Graph) SinkOrder() int {
 	return len(g.Sinks)
 }
 
-func (g *Graph) v(m map[int]*graph.Vertex, id int) *graph.Vertex {
+func (g *Graph) v(m map[int]*Vertex, id int) *Vertex {
 	if v, found := m[id]; found {
 		return v
 	}
@@ -90,43 +136,112 @@ func (g *Graph) v(m map[int]*graph.Vertex, id int) *graph.Vertex {
 	return v
 }
 
-func (g *Graph) NewEdge(source, sink int, v, f float64) *graph.Edge {
+func (g *Graph) NewEdge(source, sink int, v, f float64) *Edge {
 	vsource := g.v(g.Sources, source)
 	vsink := g.v(g.Sinks, sink)
 	e := g.Connect(vsource).To(vsink)
-	e.Data = &EdgeData{v, f}
-	key := fmt.Sprint(source, ":", sink)
-	g.EdgeMap[key] = e
+	e.Data = &EdgeData{v, f, 0., math.Inf(1)}
+	g.EdgeMap[EdgeKey(source, sink)] = e
+	g.arcs[Key{source, sink}] = e
 	return e
 }
 
-func (g *Graph) Edge(source, sink int) (*graph.Edge, string) {
-	key := EdgeKey(source, sink)
-	if e, found := g.EdgeMap[key]; found {
-		return e, key
+// Arc is the edge lookup without the string key, nil if not found.
+func (g *Graph) Arc(source, sink int) *Edge {
+	return g.arcs[Key{source, sink}]
+}
+
+func (g *Graph) Edge(source, sink int) (*Edge, string) {
+	return g.Arc(source, sink), EdgeKey(source, sink)
+}
+
+// SetCost changes the variable cost (only in the overlay), nil if the edge is not found.
+func (g *Graph) SetCost(source, sink int, v float64) *Edge {
+	e := g.Arc(source, sink)
+	if e == nil {
+		return nil
 	}
-	return nil, key
+	if g.overlay != nil {
+		g.overlay.costs[e] = v
+	} else {
+		e.Data.VCost = v
+	}
+	return e
 }
 
-func (g *Graph) EdgeCost(source, sink int, v float64) (*graph.Edge, string) {
-	key := EdgeKey(source, sink)
-	e, found := g.EdgeMap[key]
-	if !found {
-		return nil, key
+// SetBounds changes the arc bounds (shared by overlays), nil if the edge is not found.
+func (g *Graph) SetBounds(source, sink int, lower, upper float64) *Edge {
+	e := g.Arc(source, sink)
+	if e != nil {
+		e.Data.Lower = lower
+		e.Data.Upper = upper
 	}
-	_e := e.Data.(*EdgeData)
-	_e.VCost = v
-	return e, key
+	return e
+}
+
+// Capacity of the edge, the largest amount it can carry: min(supply, demand, upper).
+func (g *Graph) Capacity(e *Edge) float64 {
+	return math.Min(math.Min(e.I.Data.Size, e.J.Data.Size), e.Data.Upper)
 }
 
-func (g *Graph) SourceSize(id int, s float64) *graph.Vertex {
+func (g *Graph) EdgeCost(source, sink int, v float64) (*Edge, string) {
+	return g.SetCost(source, sink, v), EdgeKey(source, sink)
+}
+
+func (g *Graph) SourceSize(id int, s float64) *Vertex {
 	v := g.v(g.Sources, id)
-	v.Data.(*VertexData).Size = s
+	v.Data.Size = s
 	return v
 }
 
-func (g *Graph) SinkSize(id int, s float64) *graph.Vertex {
+func (g *Graph) SinkSize(id int, s float64) *Vertex {
 	v := g.v(g.Sinks, id)
-	v.Data.(*VertexData).Size = s
+	v.Data.Size = s
 	return v
 }
+
+func (g *Graph) Supply() float64 {
+	total := 0.
+	for _, v := range g.Sources {
+		total += v.Data.Size
+	}
+	return total
+}
+
+func (g *Graph) Demand() float64 {
+	total := 0.
+	for _, v := range g.Sinks {
+		total += v.Data.Size
+	}
+	return total
+}
+
+// Check reports instances that cannot be solved: unbalanced supply and demand,
+// sources and sinks without arcs, or edges unable to carry the supply.
+func (g *Graph) Check() error {
+	supply, demand := g.Supply(), g.Demand()
+	if math.Abs(supply-demand) > 0.001 {
+		return fmt.Errorf("Unbalanced supply %.2f and demand %.2f", supply, demand)
+	}
+	for _, v := range g.Sources {
+		if _v := v.Data; _v.Size > 0 && g.enabled(v.EdgeOut) == 0 {
+			return fmt.Errorf("Source %d without arcs", _v.Id)
+		}
+	}
+	for _, v := range g.Sinks {
+		if _v := v.Data; _v.Size > 0 && g.enabled(v.EdgeIn) == 0 {
+			return fmt.Errorf("Sink %d without arcs", _v.Id)
+		}
+	}
+	return g.Diagnose()
+}
+
+func (g *Graph) enabled(edges []*Edge) int {
+	n := 0
+	for _, e := range edges {
+		if g.Enabled(e) {
+			n++
+		}
+	}
+	return n
+}
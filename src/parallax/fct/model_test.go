@@ -0,0 +1,68 @@
+package fct
+
+import (
+	"fmt"
+	"testing"
+)
+
+func testGraph(n int) *Graph {
+	g := NewGraph()
+	for i := 1; i <= n; i++ {
+		for j := n + 1; j <= 2*n; j++ {
+			g.NewEdge(i, j, float64(i+j), float64(i*j))
+		}
+	}
+	return g
+}
+
+func TestEdgeLookup(t *testing.T) {
+	g := testGraph(5)
+	e, key := g.Edge(2, 8)
+	if e == nil || key != "2:8" || e != g.Arc(2, 8) || e != g.EdgeMap[key] {
+		t.Fatal("Edge not found: 2:8")
+	}
+	if e.I.Data.Id != 2 || e.J.Data.Id != 8 {
+		t.Error("Wrong edge vertices:", e.I.Data, e.J.Data)
+	}
+	if g.Arc(8, 2) != nil {
+		t.Error("Reverse edge found: 8:2")
+	}
+	g.SetCost(2, 8, 1.5)
+	if v := e.Data.VCost; v != 1.5 {
+		t.Error("Cost not changed (1.5):", v)
+	}
+	if (Key{2, 8}).String() != EdgeKey(2, 8) {
+		t.Error("Wrong key string:", Key{2, 8})
+	}
+}
+
+const benchSize = 300
+
+func BenchmarkEdgeArc(b *testing.B) {
+	g := testGraph(benchSize)
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		for _, e := range g.Edges {
+			g.Arc(e.I.Data.Id, e.J.Data.Id)
+		}
+	}
+}
+
+// Lookup by formatted string key (EdgeMap, as before Arc)
+func BenchmarkEdgeStringKey(b *testing.B) {
+	g := testGraph(benchSize)
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		for _, e := range g.Edges {
+			_ = g.EdgeMap[fmt.Sprint(e.I.Data.Id, ":", e.J.Data.Id)]
+		}
+	}
+}
+
+func BenchmarkClone(b *testing.B) {
+	g := testGraph(benchSize)
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		g.Clone()
+	}
+}
@@ -0,0 +1,53 @@
+package fct
+
+import "testing"
+
+func TestOverlay(t *testing.T) {
+	g := testGraph(3)
+	o := g.Overlay()
+	o.ScaleCosts(2.)
+	o.SetCost(1, 4, 1.)
+	o.Disable(2, 5)
+	if v := o.Cost(o.Arc(1, 4)); v != 1. {
+		t.Error("Wrong overlay cost (1):", v)
+	}
+	if v := o.Cost(o.Arc(3, 6)); v != 18. {
+		t.Error("Wrong scaled cost (18):", v)
+	}
+	if v := g.Arc(1, 4).Data.VCost; v != 5. {
+		t.Error("Base changed (5):", v)
+	}
+	if o.Enabled(o.Arc(2, 5)) || !g.Enabled(g.Arc(2, 5)) {
+		t.Error("Wrong disabled edge: 2:5")
+	}
+	if n := o.Changes(); n != 2 {
+		t.Error("Wrong number of changes (2):", n)
+	}
+
+	_o := o.Overlay()
+	_o.SetCost(3, 6, 0.)
+	if _o.Base() != g || o.Cost(o.Arc(3, 6)) != 18. || _o.Cost(_o.Arc(1, 4)) != 1. {
+		t.Error("Wrong nested overlay")
+	}
+
+	c := o.Clone()
+	if c.IsOverlay() || c.Size() != g.Size()-1 || c.Arc(2, 5) != nil {
+		t.Error("Wrong clone:", c)
+	}
+	if v := c.Arc(3, 6).Data.VCost; v != 18. {
+		t.Error("Wrong clone cost (18):", v)
+	}
+	if f := o.MaxFlow(nil); f.Flow[Key{2, 5}] != 0. {
+		t.Error("Flow on disabled edge: 2:5")
+	}
+}
+
+func BenchmarkOverlay(b *testing.B) {
+	g := testGraph(benchSize)
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		o := g.Overlay()
+		o.ScaleCosts(1.2)
+		o.SetCost(1, benchSize+1, 0.)
+	}
+}
@@ -0,0 +1,102 @@
+package fct
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Instance statistics - sizes, balance and cost distributions
+
+type Distribution struct {
+	Count                          int
+	Min, Max, Mean, StdDev, Median float64
+}
+
+func NewDistribution(values []float64) Distribution {
+	d := Distribution{Count: len(values)}
+	if d.Count == 0 {
+		return d
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	d.Min, d.Max = sorted[0], sorted[d.Count-1]
+	if d.Count%2 == 1 {
+		d.Median = sorted[d.Count/2]
+	} else {
+		d.Median = (sorted[d.Count/2-1] + sorted[d.Count/2]) / 2
+	}
+	for _, v := range sorted {
+		d.Mean += v
+	}
+	d.Mean /= float64(d.Count)
+	for _, v := range sorted {
+		d.StdDev += (v - d.Mean) * (v - d.Mean)
+	}
+	d.StdDev = math.Sqrt(d.StdDev / float64(d.Count))
+	return d
+}
+
+func (d Distribution) String() string {
+	return fmt.Sprintf("min %.2f, max %.2f, mean %.2f, sd %.2f, median %.2f", d.Min, d.Max, d.Mean, d.StdDev, d.Median)
+}
+
+type Stats struct {
+	Sources, Sinks, Arcs int
+	Supply, Demand       float64
+	Balanced             bool
+	Density              float64 // arcs / (sources * sinks)
+	VCost, FCost         Distribution
+	// Fixed cost over variable cost at full capacity, FCost / (VCost * capacity)
+	Ratio Distribution
+	// Arc bound over min(supply, demand), min(u, s, d) / min(s, d): 1 if the
+	// bound does not cap the arc, low values need more arcs
+	Tightness Distribution
+}
+
+// Stats of enabled edges with overlay costs.
+func (g *Graph) Stats() *Stats {
+	s := &Stats{
+		Sources: g.SourceOrder(),
+		Sinks:   g.SinkOrder(),
+		Supply:  g.Supply(),
+		Demand:  g.Demand(),
+	}
+	s.Balanced = math.Abs(s.Supply-s.Demand) < 0.001
+
+	edges := g.edges()
+	s.Arcs = len(edges)
+	if s.Sources > 0 && s.Sinks > 0 {
+		s.Density = float64(s.Arcs) / float64(s.Sources*s.Sinks)
+	}
+	vcost := make([]float64, 0, len(edges))
+	fcost := make([]float64, 0, len(edges))
+	ratio := make([]float64, 0, len(edges))
+	tightness := make([]float64, 0, len(edges))
+	for _, e := range edges {
+		v, f, c := g.Cost(e), e.Data.FCost, g.Capacity(e)
+		vcost = append(vcost, v)
+		fcost = append(fcost, f)
+		if v*c > 0 {
+			ratio = append(ratio, f/(v*c))
+		}
+		if m := math.Min(e.I.Data.Size, e.J.Data.Size); m > 0 {
+			tightness = append(tightness, c/m)
+		}
+	}
+	s.VCost = NewDistribution(vcost)
+	s.FCost = NewDistribution(fcost)
+	s.Ratio = NewDistribution(ratio)
+	s.Tightness = NewDistribution(tightness)
+	return s
+}
+
+func (s *Stats) String() string {
+	out := fmt.Sprintf("Sources %d, Sinks %d, Arcs %d (density %.2f)\n", s.Sources, s.Sinks, s.Arcs, s.Density)
+	out += fmt.Sprintf("Supply %.2f, Demand %.2f, Balanced %t\n", s.Supply, s.Demand, s.Balanced)
+	out += fmt.Sprintln("VCost:", s.VCost)
+	out += fmt.Sprintln("FCost:", s.FCost)
+	out += fmt.Sprintln("Fixed/Variable:", s.Ratio)
+	out += fmt.Sprintln("Tightness:", s.Tightness)
+	return out
+}
@@ -0,0 +1,39 @@
+package fct
+
+import "testing"
+
+func TestDistribution(t *testing.T) {
+	d := NewDistribution([]float64{4., 1., 3., 2.})
+	if d.Count != 4 || d.Min != 1. || d.Max != 4. || d.Mean != 2.5 || d.Median != 2.5 {
+		t.Error("Wrong distribution:", d)
+	}
+	if d := NewDistribution(nil); d.Count != 0 || d.Mean != 0. {
+		t.Error("Wrong empty distribution:", d)
+	}
+}
+
+func TestStats(t *testing.T) {
+	g := NewGraph()
+	g.SourceSize(1, 10.)
+	g.SinkSize(2, 4.)
+	g.SinkSize(3, 6.)
+	g.NewEdge(1, 2, 2., 8.)
+	g.NewEdge(1, 3, 1., 12.)
+	s := g.Stats()
+	if s.Arcs != 2 || s.Density != 1. || !s.Balanced {
+		t.Error("Wrong stats:", s)
+	}
+	// 8 / (2 * 4) and 12 / (1 * 6)
+	if r := s.Ratio; r.Min != 1. || r.Max != 2. {
+		t.Error("Wrong fixed/variable ratio (1, 2):", r)
+	}
+	// not capped
+	if r := s.Tightness; r.Min != 1. || r.Max != 1. {
+		t.Error("Wrong tightness (1, 1):", r)
+	}
+	// 3 / 6 and 4 / 4
+	g.SetBounds(1, 3, 0., 3.)
+	if r := g.Stats().Tightness; r.Min != .5 || r.Max != 1. || r.Mean != .75 {
+		t.Error("Wrong tightness (0.5, 1):", r)
+	}
+}
@@ -0,0 +1,18 @@
+package graph
+
+// Untyped graph - payloads as interface{}, kept for compatibility
+
+type Graph = Typed[interface{}, interface{}]
+type Vertex = TypedVertex[interface{}, interface{}]
+type Edge = TypedEdge[interface{}, interface{}]
+type EdgeBuilder = TypedEdgeBuilder[interface{}, interface{}]
+
+type Visitor = TypedVisitor[interface{}, interface{}]
+type Weight = TypedWeight[interface{}, interface{}]
+type Paths = TypedPaths[interface{}, interface{}]
+type Capacity = TypedCapacity[interface{}, interface{}]
+type MaxFlow = TypedMaxFlow[interface{}, interface{}]
+
+func New() *Graph {
+	return NewTyped[interface{}, interface{}]()
+}
@@ -0,0 +1,75 @@
+package graph
+
+// Graph editing - removal keeps Vertices and Edges in order and renumbers
+// Index, so slices indexed by vertex or edge must be rebuilt afterwards.
+
+// RemoveEdge returns false if the edge is not in the graph.
+func (g *Typed[V, E]) RemoveEdge(e *TypedEdge[V, E]) bool {
+	if !g.hasEdge(e) {
+		return false
+	}
+	g.removeEdges(map[*TypedEdge[V, E]]bool{e: true})
+	return true
+}
+
+// RemoveVertex also removes the edges incident to v.
+func (g *Typed[V, E]) RemoveVertex(v *TypedVertex[V, E]) bool {
+	if v == nil || v.Index >= len(g.Vertices) || g.Vertices[v.Index] != v {
+		return false
+	}
+	removed := make(map[*TypedEdge[V, E]]bool)
+	for _, edges := range [][]*TypedEdge[V, E]{v.Edges, v.EdgeOut, v.EdgeIn} {
+		for _, e := range edges {
+			removed[e] = true
+		}
+	}
+	g.removeEdges(removed)
+
+	copy(g.Vertices[v.Index:], g.Vertices[v.Index+1:])
+	g.Vertices[len(g.Vertices)-1] = nil
+	g.Vertices = g.Vertices[:len(g.Vertices)-1]
+	for i := v.Index; i < len(g.Vertices); i++ {
+		g.Vertices[i].Index = i
+	}
+	v.Graph = nil
+	return true
+}
+
+func (g *Typed[V, E]) hasEdge(e *TypedEdge[V, E]) bool {
+	return e != nil && e.Index < len(g.Edges) && g.Edges[e.Index] == e
+}
+
+func (g *Typed[V, E]) removeEdges(removed map[*TypedEdge[V, E]]bool) {
+	if len(removed) == 0 {
+		return
+	}
+	edges := g.Edges[:0]
+	for _, e := range g.Edges {
+		if removed[e] {
+			continue
+		}
+		e.Index = len(edges)
+		edges = append(edges, e)
+	}
+	for i := len(edges); i < len(g.Edges); i++ {
+		g.Edges[i] = nil
+	}
+	g.Edges = edges
+
+	for e := range removed {
+		e.I.Edges = without(e.I.Edges, e)
+		e.J.Edges = without(e.J.Edges, e)
+		e.I.EdgeOut = without(e.I.EdgeOut, e)
+		e.J.EdgeIn = without(e.J.EdgeIn, e)
+		e.Graph = nil
+	}
+}
+
+func without[V, E any](edges []*TypedEdge[V, E], e *TypedEdge[V, E]) []*TypedEdge[V, E] {
+	for i, u := range edges {
+		if u == e {
+			return append(edges[:i], edges[i+1:]...)
+		}
+	}
+	return edges
+}
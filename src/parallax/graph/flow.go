@@ -0,0 +1,237 @@
+package graph
+
+// Maximum flow - edge capacities given by a function, undirected edges carry
+// flow both ways.
+
+const flowEpsilon = 1e-9
+
+type TypedCapacity[V, E any] func(e *TypedEdge[V, E]) float64
+
+type TypedMaxFlow[V, E any] struct {
+	Value float64
+	Flow  []float64 // by edge index, negative for undirected edges used from J to I
+	Cut   []bool    // by vertex index, source side of a minimum cut
+}
+
+// Edges crossing the minimum cut (saturated), their capacities sum to Value.
+func (f *TypedMaxFlow[V, E]) CutEdges(g *Typed[V, E]) []*TypedEdge[V, E] {
+	result := make([]*TypedEdge[V, E], 0)
+	for _, e := range g.Edges {
+		i, j := f.Cut[e.I.Index], f.Cut[e.J.Index]
+		if i && !j || !i && j && e.I.isUndirected(e) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func (v *TypedVertex[V, E]) isUndirected(e *TypedEdge[V, E]) bool {
+	for _, u := range v.Edges {
+		if u == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Residual network, arc 2k is edge k (I to J) and arc 2k+1 its reverse.
+type residual struct {
+	n     int
+	head  []int
+	cap   []float64
+	adj   [][]int
+	limit []float64
+}
+
+func newResidual[V, E any](g *Typed[V, E], c TypedCapacity[V, E]) *residual {
+	r := &residual{
+		g.Order(),
+		make([]int, 2*g.Size()),
+		make([]float64, 2*g.Size()),
+		make([][]int, g.Order()),
+		make([]float64, g.Size()),
+	}
+	for _, e := range g.Edges {
+		k := 2 * e.Index
+		r.limit[e.Index] = c(e)
+		r.head[k], r.head[k+1] = e.J.Index, e.I.Index
+		r.cap[k] = r.limit[e.Index]
+		r.adj[e.I.Index] = append(r.adj[e.I.Index], k)
+		r.adj[e.J.Index] = append(r.adj[e.J.Index], k+1)
+	}
+	for _, v := range g.Vertices {
+		for _, e := range v.Edges {
+			if e.I == v {
+				r.cap[2*e.Index+1] = r.limit[e.Index]
+			}
+		}
+	}
+	return r
+}
+
+func (r *residual) push(k int, amount float64) {
+	r.cap[k] -= amount
+	r.cap[k^1] += amount
+}
+
+func result[V, E any](r *residual, g *Typed[V, E], s int, value float64) *TypedMaxFlow[V, E] {
+	f := &TypedMaxFlow[V, E]{value, make([]float64, g.Size()), make([]bool, r.n)}
+	for _, e := range g.Edges {
+		f.Flow[e.Index] = r.limit[e.Index] - r.cap[2*e.Index]
+	}
+	f.Cut[s] = true
+	queue := []int{s}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, k := range r.adj[u] {
+			if v := r.head[k]; r.cap[k] > flowEpsilon && !f.Cut[v] {
+				f.Cut[v] = true
+				queue = append(queue, v)
+			}
+		}
+	}
+	return f
+}
+
+func Dinic[V, E any](g *Typed[V, E], s, t *TypedVertex[V, E], c TypedCapacity[V, E]) *TypedMaxFlow[V, E] {
+	r := newResidual(g, c)
+	level := make([]int, r.n)
+	next := make([]int, r.n)
+
+	bfs := func() bool {
+		for i := range level {
+			level[i] = -1
+		}
+		level[s.Index] = 0
+		queue := []int{s.Index}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, k := range r.adj[u] {
+				if v := r.head[k]; r.cap[k] > flowEpsilon && level[v] < 0 {
+					level[v] = level[u] + 1
+					queue = append(queue, v)
+				}
+			}
+		}
+		return level[t.Index] >= 0
+	}
+
+	var dfs func(u int, limit float64) float64
+	dfs = func(u int, limit float64) float64 {
+		if u == t.Index {
+			return limit
+		}
+		for ; next[u] < len(r.adj[u]); next[u]++ {
+			k := r.adj[u][next[u]]
+			v := r.head[k]
+			if r.cap[k] <= flowEpsilon || level[v] != level[u]+1 {
+				continue
+			}
+			amount := limit
+			if r.cap[k] < amount {
+				amount = r.cap[k]
+			}
+			if pushed := dfs(v, amount); pushed > flowEpsilon {
+				r.push(k, pushed)
+				return pushed
+			}
+		}
+		return 0.
+	}
+
+	value := 0.
+	if s != t {
+		for bfs() {
+			for i := range next {
+				next[i] = 0
+			}
+			for {
+				pushed := dfs(s.Index, infinity(r))
+				if pushed <= flowEpsilon {
+					break
+				}
+				value += pushed
+			}
+		}
+	}
+	return result(r, g, s.Index, value)
+}
+
+// FIFO push-relabel, excess that cannot reach t returns to s.
+func PushRelabel[V, E any](g *Typed[V, E], s, t *TypedVertex[V, E], c TypedCapacity[V, E]) *TypedMaxFlow[V, E] {
+	r := newResidual(g, c)
+	height := make([]int, r.n)
+	excess := make([]float64, r.n)
+	active := make([]bool, r.n)
+	queue := make([]int, 0)
+
+	push := func(u, k int, amount float64) {
+		v := r.head[k]
+		r.push(k, amount)
+		excess[u] -= amount
+		excess[v] += amount
+		if !active[v] && v != s.Index && v != t.Index {
+			active[v] = true
+			queue = append(queue, v)
+		}
+	}
+
+	if s != t {
+		height[s.Index] = r.n
+		for _, k := range r.adj[s.Index] {
+			if r.cap[k] > flowEpsilon {
+				excess[s.Index] += r.cap[k]
+				push(s.Index, k, r.cap[k])
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		active[u] = false
+		for excess[u] > flowEpsilon {
+			lowest := -1
+			for _, k := range r.adj[u] {
+				if r.cap[k] <= flowEpsilon {
+					continue
+				}
+				v := r.head[k]
+				if height[u] == height[v]+1 {
+					amount := excess[u]
+					if r.cap[k] < amount {
+						amount = r.cap[k]
+					}
+					push(u, k, amount)
+					if excess[u] <= flowEpsilon {
+						break
+					}
+				} else if lowest < 0 || height[v] < lowest {
+					lowest = height[v]
+				}
+			}
+			if excess[u] > flowEpsilon {
+				if lowest < 0 {
+					break
+				}
+				height[u] = lowest + 1
+			}
+		}
+	}
+
+	value := 0.
+	if s != t {
+		value = excess[t.Index]
+	}
+	return result(r, g, s.Index, value)
+}
+
+func infinity(r *residual) float64 {
+	total := 1.
+	for _, c := range r.limit {
+		total += c
+	}
+	return 2 * total
+}
@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func testNetwork() (*Graph, []*Vertex, Capacity) {
+	// classic CLRS network, max flow 23
+	g := New()
+	v := make([]*Vertex, 6)
+	for i := range v {
+		v[i] = g.Vertex()
+	}
+	capacity := []float64{16, 13, 10, 12, 4, 9, 14, 7, 20, 4}
+	g.Connect(v[0]).To(v[1])
+	g.Connect(v[0]).To(v[2])
+	g.Connect(v[2]).To(v[1])
+	g.Connect(v[1]).To(v[3])
+	g.Connect(v[1]).To(v[2])
+	g.Connect(v[3]).To(v[2])
+	g.Connect(v[2]).To(v[4])
+	g.Connect(v[4]).To(v[3])
+	g.Connect(v[3]).To(v[5])
+	g.Connect(v[4]).To(v[5])
+	return g, v, func(e *Edge) float64 { return capacity[e.Index] }
+}
+
+func checkFlow(t *testing.T, g *Graph, s, tt *Vertex, c Capacity, f *MaxFlow) {
+	balance := make([]float64, g.Order())
+	for _, e := range g.Edges {
+		m := f.Flow[e.Index]
+		if m < -flowEpsilon || m > c(e)+flowEpsilon {
+			t.Error("Flow out of bounds:", e.Index, m)
+		}
+		balance[e.I.Index] -= m
+		balance[e.J.Index] += m
+	}
+	for _, v := range g.Vertices {
+		if v != s && v != tt && math.Abs(balance[v.Index]) > 1e-6 {
+			t.Error("Flow not conserved:", v.Index, balance[v.Index])
+		}
+	}
+	if math.Abs(balance[tt.Index]-f.Value) > 1e-6 {
+		t.Error("Wrong flow value:", balance[tt.Index], f.Value)
+	}
+	cut := 0.
+	for _, e := range f.CutEdges(g) {
+		cut += c(e)
+	}
+	if math.Abs(cut-f.Value) > 1e-6 {
+		t.Error("Cut differs from flow:", cut, f.Value)
+	}
+}
+
+func TestDinic(t *testing.T) {
+	g, v, c := testNetwork()
+	f := Dinic(g, v[0], v[5], c)
+	if f.Value != 23. {
+		t.Error("Wrong max flow (23):", f.Value)
+	}
+	checkFlow(t, g, v[0], v[5], c, f)
+}
+
+func TestPushRelabel(t *testing.T) {
+	g, v, c := testNetwork()
+	f := PushRelabel(g, v[0], v[5], c)
+	if f.Value != 23. {
+		t.Error("Wrong max flow (23):", f.Value)
+	}
+	checkFlow(t, g, v[0], v[5], c, f)
+}
+
+func TestMaxFlowRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for k := 0; k < 20; k++ {
+		g := New()
+		for i := 0; i < 12; i++ {
+			g.Vertex()
+		}
+		capacity := make([]float64, 0)
+		for i := 0; i < 40; i++ {
+			a, b := rnd.Intn(12), rnd.Intn(12)
+			if a != b {
+				g.Connect(g.Vertices[a]).To(g.Vertices[b])
+				capacity = append(capacity, float64(rnd.Intn(20)))
+			}
+		}
+		c := func(e *Edge) float64 { return capacity[e.Index] }
+		s, tt := g.Vertices[0], g.Vertices[11]
+		d := Dinic(g, s, tt, c)
+		p := PushRelabel(g, s, tt, c)
+		if math.Abs(d.Value-p.Value) > 1e-6 {
+			t.Error("Different max flow:", d.Value, p.Value)
+		}
+		checkFlow(t, g, s, tt, c, d)
+		checkFlow(t, g, s, tt, c, p)
+	}
+}
@@ -4,33 +4,37 @@ import (
 	"fmt"
 )
 
-type Vertex struct {
-	Graph   *Graph
-	Edges   []*Edge
-	EdgeOut []*Edge
-	EdgeIn  []*Edge
-	Data    interface{}
+// Graph with vertex payload V and edge payload E
+
+type TypedVertex[V, E any] struct {
+	Graph   *Typed[V, E]
+	Index   int
+	Edges   []*TypedEdge[V, E]
+	EdgeOut []*TypedEdge[V, E]
+	EdgeIn  []*TypedEdge[V, E]
+	Data    V
 }
 
-func (v *Vertex) Degree() int {
+func (v *TypedVertex[V, E]) Degree() int {
 	return len(v.Edges) + len(v.EdgeOut) + len(v.EdgeIn)
 }
 
-func (v *Vertex) OutDegree() int {
+func (v *TypedVertex[V, E]) OutDegree() int {
 	return len(v.Edges) + len(v.EdgeOut)
 }
 
-func (v *Vertex) InDegree() int {
+func (v *TypedVertex[V, E]) InDegree() int {
 	return len(v.Edges) + len(v.EdgeIn)
 }
 
-type Edge struct {
-	Graph *Graph
-	I, J  *Vertex
-	Data  interface{}
+type TypedEdge[V, E any] struct {
+	Graph *Typed[V, E]
+	Index int
+	I, J  *TypedVertex[V, E]
+	Data  E
 }
 
-func (e *Edge) Other(v *Vertex) *Vertex {
+func (e *TypedEdge[V, E]) Other(v *TypedVertex[V, E]) *TypedVertex[V, E] {
 	switch v {
 	case e.I:
 		return e.J
@@ -41,54 +45,54 @@ func (e *Edge) Other(v *Vertex) *Vertex {
 	}
 }
 
-type Graph struct {
-	Vertices []*Vertex
-	Edges    []*Edge
+type Typed[V, E any] struct {
+	Vertices []*TypedVertex[V, E]
+	Edges    []*TypedEdge[V, E]
 }
 
-func New() *Graph {
-	return &Graph{
-		make([]*Vertex, 0),
-		make([]*Edge, 0),
+func NewTyped[V, E any]() *Typed[V, E] {
+	return &Typed[V, E]{
+		make([]*TypedVertex[V, E], 0),
+		make([]*TypedEdge[V, E], 0),
 	}
 }
 
-func (g *Graph) String() string {
+func (g *Typed[V, E]) String() string {
 	return fmt.Sprintf("G(V, E) = [%d, %d]", g.Order(), g.Size())
 }
 
-func (g *Graph) Order() int {
+func (g *Typed[V, E]) Order() int {
 	return len(g.Vertices)
 }
 
-func (g *Graph) Size() int {
+func (g *Typed[V, E]) Size() int {
 	return len(g.Edges)
 }
 
-func (g *Graph) Vertex() *Vertex {
-	v := &Vertex{
-		g,
-		make([]*Edge, 0),
-		make([]*Edge, 0),
-		make([]*Edge, 0),
-		nil,
+func (g *Typed[V, E]) Vertex() *TypedVertex[V, E] {
+	v := &TypedVertex[V, E]{
+		Graph:   g,
+		Index:   len(g.Vertices),
+		Edges:   make([]*TypedEdge[V, E], 0),
+		EdgeOut: make([]*TypedEdge[V, E], 0),
+		EdgeIn:  make([]*TypedEdge[V, E], 0),
 	}
 	g.Vertices = append(g.Vertices, v)
 	return v
 }
 
-func (g *Graph) edge(vi, vj *Vertex) *Edge {
-	e := &Edge{g, vi, vj, nil}
+func (g *Typed[V, E]) edge(vi, vj *TypedVertex[V, E]) *TypedEdge[V, E] {
+	e := &TypedEdge[V, E]{Graph: g, Index: len(g.Edges), I: vi, J: vj}
 	g.Edges = append(g.Edges, e)
 	return e
 }
 
-type EdgeBuilder struct {
-	g *Graph
-	v *Vertex
+type TypedEdgeBuilder[V, E any] struct {
+	g *Typed[V, E]
+	v *TypedVertex[V, E]
 }
 
-func (b *EdgeBuilder) With(other *Vertex) *Edge {
+func (b *TypedEdgeBuilder[V, E]) With(other *TypedVertex[V, E]) *TypedEdge[V, E] {
 	// undirected
 	one := b.v
 	e := b.g.edge(one, other)
@@ -97,7 +101,7 @@ func (b *EdgeBuilder) With(other *Vertex) *Edge {
 	return e
 }
 
-func (b *EdgeBuilder) To(head *Vertex) *Edge {
+func (b *TypedEdgeBuilder[V, E]) To(head *TypedVertex[V, E]) *TypedEdge[V, E] {
 	// directed
 	tail := b.v
 	e := b.g.edge(tail, head)
@@ -106,6 +110,6 @@ func (b *EdgeBuilder) To(head *Vertex) *Edge {
 	return e
 }
 
-func (g *Graph) Connect(v *Vertex) *EdgeBuilder {
-	return &EdgeBuilder{g, v}
+func (g *Typed[V, E]) Connect(v *TypedVertex[V, E]) *TypedEdgeBuilder[V, E] {
+	return &TypedEdgeBuilder[V, E]{g, v}
 }
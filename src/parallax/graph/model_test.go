@@ -116,3 +116,52 @@ func TestNewDirectedEdge(t *testing.T) {
 		t.Error("Graph missing edge (1):", n)
 	}
 }
+
+func TestTypedGraph(t *testing.T) {
+	g := NewTyped[string, float64]()
+	v1 := g.Vertex()
+	v1.Data = "source"
+	v2 := g.Vertex()
+	v2.Data = "sink"
+	e := g.Connect(v1).To(v2)
+	e.Data = 2.5
+	if s := e.Other(v1).Data; s != "sink" {
+		t.Error("Wrong vertex payload (sink):", s)
+	}
+	if w := v2.EdgeIn[0].Data; w != 2.5 {
+		t.Error("Wrong edge payload (2.5):", w)
+	}
+	p, err := BellmanFord(g, v1, func(e *TypedEdge[string, float64]) float64 { return e.Data })
+	if err != nil || p.Dist[v2.Index] != 2.5 {
+		t.Error("Wrong typed distance (2.5):", p.Dist, err)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	g := New()
+	v1, v2, v3 := g.Vertex(), g.Vertex(), g.Vertex()
+	e1 := g.Connect(v1).To(v2)
+	e2 := g.Connect(v2).To(v3)
+	e3 := g.Connect(v1).With(v3)
+	if !g.RemoveEdge(e1) || g.RemoveEdge(e1) {
+		t.Fatal("Error removing edge e1")
+	}
+	if n := g.Size(); n != 2 || e2.Index != 0 || e3.Index != 1 {
+		t.Error("Wrong edges after removal (2):", n, e2.Index, e3.Index)
+	}
+	if d := v1.OutDegree(); d != 1 {
+		t.Error("Vertex v1 exceeding outdegree (1):", d)
+	}
+	if d := v2.InDegree(); d != 0 {
+		t.Error("Vertex v2 exceeding indegree (0):", d)
+	}
+	if !g.RemoveVertex(v2) || g.RemoveVertex(v2) {
+		t.Fatal("Error removing vertex v2")
+	}
+	if n, m := g.Order(), g.Size(); n != 2 || m != 1 || g.Edges[0] != e3 {
+		t.Error("Wrong graph after removal (2, 1):", n, m)
+	}
+	if v3.Index != 1 || v3.InDegree() != 1 {
+		t.Error("Wrong vertex v3 after removal:", v3.Index, v3.InDegree())
+	}
+}
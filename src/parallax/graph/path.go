@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+)
+
+// Shortest paths - edge weights given by a function (e.g. fct.VCost)
+
+var ErrNegativeCycle = errors.New("Graph has a negative cycle")
+
+type TypedWeight[V, E any] func(e *TypedEdge[V, E]) float64
+
+type TypedPaths[V, E any] struct {
+	Source *TypedVertex[V, E]
+	Dist   []float64
+	Pred   []*TypedEdge[V, E]
+}
+
+func newPaths[V, E any](g *Typed[V, E], s *TypedVertex[V, E]) *TypedPaths[V, E] {
+	p := &TypedPaths[V, E]{s, make([]float64, g.Order()), make([]*TypedEdge[V, E], g.Order())}
+	for i := range p.Dist {
+		p.Dist[i] = math.Inf(1)
+	}
+	p.Dist[s.Index] = 0.
+	return p
+}
+
+func (p *TypedPaths[V, E]) Reached(v *TypedVertex[V, E]) bool {
+	return !math.IsInf(p.Dist[v.Index], 1)
+}
+
+// Edges from the source to v, nil if v is not reached.
+func (p *TypedPaths[V, E]) PathTo(v *TypedVertex[V, E]) []*TypedEdge[V, E] {
+	if !p.Reached(v) {
+		return nil
+	}
+	result := make([]*TypedEdge[V, E], 0)
+	for v != p.Source {
+		e := p.Pred[v.Index]
+		result = append(result, e)
+		v = e.Other(v)
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+func BellmanFord[V, E any](g *Typed[V, E], s *TypedVertex[V, E], w TypedWeight[V, E]) (*TypedPaths[V, E], error) {
+	p := newPaths(g, s)
+	relax := func() bool {
+		changed := false
+		for _, v := range g.Vertices {
+			d := p.Dist[v.Index]
+			if math.IsInf(d, 1) {
+				continue
+			}
+			v.out(func(e *TypedEdge[V, E], u *TypedVertex[V, E]) {
+				if nd := d + w(e); nd < p.Dist[u.Index] {
+					p.Dist[u.Index] = nd
+					p.Pred[u.Index] = e
+					changed = true
+				}
+			})
+		}
+		return changed
+	}
+	for k := 1; k < g.Order(); k++ {
+		if !relax() {
+			return p, nil
+		}
+	}
+	if relax() {
+		return nil, ErrNegativeCycle
+	}
+	return p, nil
+}
+
+// Dijkstra requires non-negative reduced weights w(e) + potential[I] - potential[J]
+// (potential may be nil), distances are reported with the original weights.
+func Dijkstra[V, E any](g *Typed[V, E], s *TypedVertex[V, E], w TypedWeight[V, E], potential []float64) *TypedPaths[V, E] {
+	pi := func(v *TypedVertex[V, E]) float64 {
+		if potential == nil {
+			return 0.
+		}
+		return potential[v.Index]
+	}
+	p := newPaths(g, s)
+	done := make([]bool, g.Order())
+	q := &vertexQueue[V, E]{}
+	heap.Push(q, &vertexItem[V, E]{s, 0.})
+	for q.Len() > 0 {
+		item := heap.Pop(q).(*vertexItem[V, E])
+		v := item.v
+		if done[v.Index] {
+			continue
+		}
+		done[v.Index] = true
+		d := item.dist
+		v.out(func(e *TypedEdge[V, E], u *TypedVertex[V, E]) {
+			if done[u.Index] {
+				return
+			}
+			nd := d + w(e) + pi(v) - pi(u)
+			if nd < p.Dist[u.Index] {
+				p.Dist[u.Index] = nd
+				p.Pred[u.Index] = e
+				heap.Push(q, &vertexItem[V, E]{u, nd})
+			}
+		})
+	}
+	for _, v := range g.Vertices {
+		if p.Reached(v) {
+			p.Dist[v.Index] += pi(v) - pi(s)
+		}
+	}
+	return p
+}
+
+type vertexItem[V, E any] struct {
+	v    *TypedVertex[V, E]
+	dist float64
+}
+
+type vertexQueue[V, E any] []*vertexItem[V, E]
+
+func (q vertexQueue[V, E]) Len() int            { return len(q) }
+func (q vertexQueue[V, E]) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q vertexQueue[V, E]) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *vertexQueue[V, E]) Push(x interface{}) { *q = append(*q, x.(*vertexItem[V, E])) }
+
+func (q *vertexQueue[V, E]) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
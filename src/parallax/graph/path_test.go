@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"testing"
+)
+
+func testWeights(g *Graph, weights ...float64) Weight {
+	return func(e *Edge) float64 {
+		return weights[e.Index]
+	}
+}
+
+func TestBellmanFord(t *testing.T) {
+	g, v := testDAG()
+	w := testWeights(g, 1., 4., 5., -2.)
+	p, err := BellmanFord(g, v[0], w)
+	if err != nil {
+		t.Fatal("Error computing paths:", err)
+	}
+	if d := p.Dist[3]; d != 2. {
+		t.Error("Wrong distance (2):", d)
+	}
+	if path := p.PathTo(v[3]); len(path) != 2 || path[0].J != v[2] {
+		t.Error("Wrong path to v3:", path)
+	}
+	if p.Reached(v[4]) || p.PathTo(v[4]) != nil {
+		t.Error("Unreachable vertex reached")
+	}
+
+	g.Connect(v[3]).To(v[0])
+	w = testWeights(g, 1., 4., 5., -2., -3.)
+	if _, err := BellmanFord(g, v[0], w); err != ErrNegativeCycle {
+		t.Error("Missing negative cycle error:", err)
+	}
+}
+
+func TestDijkstra(t *testing.T) {
+	g, v := testDAG()
+	w := testWeights(g, 1., 4., 5., 1.)
+	p := Dijkstra(g, v[0], w, nil)
+	if d := p.Dist[3]; d != 5. {
+		t.Error("Wrong distance (5):", d)
+	}
+
+	// negative weights with feasible potentials from Bellman-Ford
+	w = testWeights(g, 1., 4., 5., -2.)
+	b, _ := BellmanFord(g, v[0], w)
+	potential := make([]float64, g.Order())
+	for i, d := range b.Dist {
+		if b.Reached(g.Vertices[i]) {
+			potential[i] = d
+		}
+	}
+	p = Dijkstra(g, v[0], w, potential)
+	for i := 0; i < 4; i++ {
+		if p.Dist[i] != b.Dist[i] {
+			t.Error("Wrong distance:", i, p.Dist[i], b.Dist[i])
+		}
+	}
+}
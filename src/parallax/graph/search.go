@@ -0,0 +1,130 @@
+package graph
+
+import (
+	"errors"
+)
+
+// Graph traversal - undirected edges are followed both ways, directed edges
+// only from I to J.
+
+var ErrCycle = errors.New("Graph has a cycle")
+
+// TypedVisitor receives each reached vertex and the edge used to reach it (nil for
+// the start vertex), returning false stops the search.
+type TypedVisitor[V, E any] func(v *TypedVertex[V, E], e *TypedEdge[V, E]) bool
+
+func (v *TypedVertex[V, E]) out(f func(e *TypedEdge[V, E], w *TypedVertex[V, E])) {
+	for _, e := range v.Edges {
+		f(e, e.Other(v))
+	}
+	for _, e := range v.EdgeOut {
+		f(e, e.J)
+	}
+}
+
+func (v *TypedVertex[V, E]) around(f func(e *TypedEdge[V, E], w *TypedVertex[V, E])) {
+	v.out(f)
+	for _, e := range v.EdgeIn {
+		f(e, e.I)
+	}
+}
+
+func BFS[V, E any](g *Typed[V, E], s *TypedVertex[V, E], visit TypedVisitor[V, E]) {
+	seen := make([]bool, g.Order())
+	seen[s.Index] = true
+	if !visit(s, nil) {
+		return
+	}
+	queue := []*TypedVertex[V, E]{s}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		stop := false
+		v.out(func(e *TypedEdge[V, E], w *TypedVertex[V, E]) {
+			if stop || seen[w.Index] {
+				return
+			}
+			seen[w.Index] = true
+			if !visit(w, e) {
+				stop = true
+				return
+			}
+			queue = append(queue, w)
+		})
+		if stop {
+			return
+		}
+	}
+}
+
+// Vertices are visited in depth-first preorder.
+func DFS[V, E any](g *Typed[V, E], s *TypedVertex[V, E], visit TypedVisitor[V, E]) {
+	seen := make([]bool, g.Order())
+	dfs(s, nil, seen, visit)
+}
+
+func dfs[V, E any](v *TypedVertex[V, E], from *TypedEdge[V, E], seen []bool, visit TypedVisitor[V, E]) bool {
+	seen[v.Index] = true
+	if !visit(v, from) {
+		return false
+	}
+	ok := true
+	v.out(func(e *TypedEdge[V, E], w *TypedVertex[V, E]) {
+		if ok && !seen[w.Index] {
+			ok = dfs(w, e, seen, visit)
+		}
+	})
+	return ok
+}
+
+// Weakly connected components, edge direction is ignored.
+func Components[V, E any](g *Typed[V, E]) [][]*TypedVertex[V, E] {
+	seen := make([]bool, g.Order())
+	result := make([][]*TypedVertex[V, E], 0)
+	for _, s := range g.Vertices {
+		if seen[s.Index] {
+			continue
+		}
+		seen[s.Index] = true
+		c := []*TypedVertex[V, E]{s}
+		for k := 0; k < len(c); k++ {
+			c[k].around(func(e *TypedEdge[V, E], w *TypedVertex[V, E]) {
+				if !seen[w.Index] {
+					seen[w.Index] = true
+					c = append(c, w)
+				}
+			})
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// Topological order of the directed edges (Kahn), undirected edges are cycles.
+func TopologicalOrder[V, E any](g *Typed[V, E]) ([]*TypedVertex[V, E], error) {
+	in := make([]int, g.Order())
+	for _, v := range g.Vertices {
+		if len(v.Edges) > 0 {
+			return nil, ErrCycle
+		}
+		in[v.Index] = len(v.EdgeIn)
+	}
+	result := make([]*TypedVertex[V, E], 0, g.Order())
+	for _, v := range g.Vertices {
+		if in[v.Index] == 0 {
+			result = append(result, v)
+		}
+	}
+	for k := 0; k < len(result); k++ {
+		for _, e := range result[k].EdgeOut {
+			in[e.J.Index]--
+			if in[e.J.Index] == 0 {
+				result = append(result, e.J)
+			}
+		}
+	}
+	if len(result) < g.Order() {
+		return nil, ErrCycle
+	}
+	return result, nil
+}
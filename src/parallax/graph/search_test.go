@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"testing"
+)
+
+// 0 -> 1 -> 3, 0 -> 2 -> 3, 4 - 5
+func testDAG() (*Graph, []*Vertex) {
+	g := New()
+	v := make([]*Vertex, 6)
+	for i := range v {
+		v[i] = g.Vertex()
+	}
+	g.Connect(v[0]).To(v[1])
+	g.Connect(v[0]).To(v[2])
+	g.Connect(v[1]).To(v[3])
+	g.Connect(v[2]).To(v[3])
+	return g, v
+}
+
+func TestBFS(t *testing.T) {
+	g, v := testDAG()
+	order := make([]int, 0)
+	BFS(g, v[0], func(u *Vertex, e *Edge) bool {
+		order = append(order, u.Index)
+		return true
+	})
+	if len(order) != 4 || order[0] != 0 || order[3] != 3 {
+		t.Error("Wrong BFS order:", order)
+	}
+	k := 0
+	BFS(g, v[0], func(u *Vertex, e *Edge) bool {
+		k++
+		return k < 2
+	})
+	if k != 2 {
+		t.Error("BFS not stopped (2):", k)
+	}
+}
+
+func TestDFS(t *testing.T) {
+	g, v := testDAG()
+	order := make([]int, 0)
+	DFS(g, v[0], func(u *Vertex, e *Edge) bool {
+		order = append(order, u.Index)
+		return true
+	})
+	if len(order) != 4 || order[0] != 0 || order[1] != 1 || order[2] != 3 {
+		t.Error("Wrong DFS order:", order)
+	}
+}
+
+func TestComponents(t *testing.T) {
+	g, v := testDAG()
+	g.Connect(v[4]).With(v[5])
+	c := Components(g)
+	if len(c) != 2 || len(c[0]) != 4 || len(c[1]) != 2 {
+		t.Error("Wrong components:", c)
+	}
+}
+
+func TestTopologicalOrder(t *testing.T) {
+	g, v := testDAG()
+	order, err := TopologicalOrder(g)
+	if err != nil {
+		t.Fatal("Error sorting DAG:", err)
+	}
+	pos := make([]int, g.Order())
+	for i, u := range order {
+		pos[u.Index] = i
+	}
+	for _, e := range g.Edges {
+		if pos[e.I.Index] > pos[e.J.Index] {
+			t.Error("Edge out of order:", e.I.Index, e.J.Index)
+		}
+	}
+	g.Connect(v[3]).To(v[0])
+	if _, err := TopologicalOrder(g); err != ErrCycle {
+		t.Error("Missing cycle error:", err)
+	}
+}
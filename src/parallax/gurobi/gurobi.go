@@ -159,6 +159,41 @@ func (v *Var) Value() (float64, error) {
 	return float64(value), nil
 }
 
+func (v *Var) setDoubleAttr(attr string, value float64) error {
+	i, err := v.Index()
+	if err != nil {
+		return err
+	}
+	ATTR := C.CString(attr)
+	defer C.free(unsafe.Pointer(ATTR))
+	result := int(C.GRBsetdblattrelement(v.model.model, ATTR, C.int(i), C.double(value)))
+	if result != 0 {
+		return v.model.env.error(result)
+	}
+	return nil
+}
+
+// SetObj changes the objective coefficient, the model keeps the last basis
+// (warm start on the next Optimize).
+func (v *Var) SetObj(obj float64) error {
+	if err := v.setDoubleAttr("Obj", obj); err != nil {
+		return err
+	}
+	v.obj = obj
+	return nil
+}
+
+func (v *Var) SetBounds(lower, upper float64) error {
+	if err := v.setDoubleAttr("LB", lower); err != nil {
+		return err
+	}
+	if err := v.setDoubleAttr("UB", upper); err != nil {
+		return err
+	}
+	v.lower, v.upper = lower, upper
+	return nil
+}
+
 func (m *Model) addVar(name string, t int, obj, lower, upper float64) *Var {
 	cname := C.CString(name)
 	ctype := C.char(t)
@@ -151,3 +151,47 @@ func TestQuickStart(t *testing.T) {
 	}
 	t.Logf("z: %f\n", vz)
 }
+
+func TestReoptimize(t *testing.T) {
+	env, _ := NewEnv("test.log")
+	defer env.Dispose()
+	model, _ := NewModel(env, "Model Test")
+	defer model.Dispose()
+
+	/* minimize: x + 2 y, x + y = 1 */
+	x := model.AddContVar("x", 1., 0., 1.)
+	y := model.AddContVar("y", 2., 0., 1.)
+	model.SetMinimize()
+	model.Update()
+	model.AddConstr("1", ConstrExpr{x: 1., y: 1.}, EQUAL, 1.)
+	model.Optimize()
+
+	/* minimize: 3 x + 2 y */
+	if err := x.SetObj(3.); err != nil {
+		t.Fatal("Error changing objective:", err)
+	}
+	model.Optimize()
+	obj, err := model.ObjectiveValue()
+	if err != nil {
+		t.Fatal("Error reading Optimal Objective:", err)
+	}
+	if obj != 2. {
+		t.Error("Wrong objective after cost change (2):", obj)
+	}
+
+	/* y <= 0.5 */
+	if err := y.SetBounds(0., 0.5); err != nil {
+		t.Fatal("Error changing bounds:", err)
+	}
+	model.Optimize()
+	obj, err = model.ObjectiveValue()
+	if err != nil {
+		t.Fatal("Error reading Optimal Objective:", err)
+	}
+	if obj != 2.5 {
+		t.Error("Wrong objective after bound change (2.5):", obj)
+	}
+	if vy, err := y.Value(); err != nil || vy != 0.5 {
+		t.Error("Wrong y after bound change (0.5):", vy, err)
+	}
+}
@@ -0,0 +1,92 @@
+package heuristic
+
+import (
+	"parallax/core"
+	"parallax/fct"
+	"time"
+)
+
+// Dynamic slope scaling - fixed costs linearized by the flow of the previous
+// solve: the arc cost is v + f / x for arcs carrying x, unused arcs keep the
+// last slope (v + f / capacity at the start, the linear relaxation). Solves
+// repeat until the arc set stabilizes, the best FCTP objective is kept.
+
+const SOLVER_SLOPE_SCALING = "SlopeScaling"
+
+type SlopeScaling struct {
+	Iterations int
+	solver     core.Solver
+}
+
+func NewSlopeScaling(solver core.Solver, iterations int) *SlopeScaling {
+	return &SlopeScaling{iterations, solver}
+}
+
+func (s *SlopeScaling) ComputeFlow(g *fct.Graph) ([]*core.EdgeFlow, error) {
+	r, err := s.Search(g)
+	if r == nil {
+		return nil, err
+	}
+	return r.Flow, err
+}
+
+// Search returns the best flow found, with the error if a solve fails after
+// the first (nil result if the first fails).
+
+func (s *SlopeScaling) Search(g *fct.Graph) (*Result, error) {
+	start := time.Now()
+	o := core.LinearRelaxation(g)
+	flow, err := s.solver.ComputeFlow(o)
+	if err != nil {
+		return nil, err
+	}
+	cost := core.Objective(g, flow)
+	result := &Result{Flow: flow, Objective: cost, Start: cost}
+	arcs := arcSet(flow)
+	for k := 1; k <= s.Iterations; k++ {
+		result.Iterations = k
+		for _, f := range flow {
+			if e := g.Arc(f.Source, f.Sink); e != nil && f.Amount > 0 {
+				o.SetCost(f.Source, f.Sink, g.Cost(e)+e.Data.FCost/f.Amount)
+			}
+		}
+		flow, err = s.solver.ComputeFlow(o)
+		if err != nil {
+			result.Time = time.Since(start)
+			return result, err
+		}
+		if cost := core.Objective(g, flow); cost < result.Objective-1e-9 {
+			result.Flow, result.Objective = flow, cost
+			result.Improvements++
+		}
+		next := arcSet(flow)
+		if sameArcs(arcs, next) {
+			break
+		}
+		arcs = next
+	}
+	result.Time = time.Since(start)
+	return result, nil
+}
+
+func arcSet(flow []*core.EdgeFlow) map[fct.Key]bool {
+	result := make(map[fct.Key]bool, len(flow))
+	for _, f := range flow {
+		if f.Amount > 0 {
+			result[fct.Key{Source: f.Source, Sink: f.Sink}] = true
+		}
+	}
+	return result
+}
+
+func sameArcs(a, b map[fct.Key]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key := range a {
+		if !b[key] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package heuristic
+
+import (
+	"errors"
+	"math"
+	"parallax/conformance"
+	"parallax/core"
+	"parallax/fct"
+	"testing"
+)
+
+func TestSlopeScaling(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		g := conformance.Generate(seed, 3, 3)
+		r, err := NewSlopeScaling(core.NewSSPSolver(), 50).Search(g)
+		if err != nil {
+			t.Fatal("Error searching:", err)
+		}
+		if v := core.Verify(g, r.Flow); !v.Feasible() || math.Abs(v.Total-r.Objective) > 1e-6 {
+			t.Error("Wrong flow:", r, v)
+		}
+		if optimum := conformance.Optimum(g); r.Objective < optimum-1e-6 || r.Objective > r.Start+1e-6 {
+			t.Error("Wrong objective:", r, "optimum", optimum)
+		}
+	}
+}
+
+func TestSlopeScalingStable(t *testing.T) {
+	g, err := fct.LoadGraph("../bundle/data/N104.DAT", 0)
+	if err != nil {
+		t.Fatal("Error loading N104:", err)
+	}
+	for _, name := range []string{core.SOLVER_SSP, core.SOLVER_COST_SCALING} {
+		r, err := NewSlopeScaling(core.NewSolver(name), 100).Search(g)
+		if err != nil {
+			t.Fatal(name, "error searching:", err)
+		}
+		if r.Iterations == 100 || r.Objective > r.Start {
+			t.Error(name, "arc set not stable:", r)
+		}
+		if v := core.Verify(g, r.Flow); !v.Feasible() {
+			t.Error(name, "wrong flow:", v)
+		}
+	}
+}
+
+// Fails after the given number of solves
+type failingSolver struct {
+	solves int
+}
+
+func (s *failingSolver) ComputeFlow(g *fct.Graph) ([]*core.EdgeFlow, error) {
+	if s.solves--; s.solves < 0 {
+		return nil, errors.New("Solver failed")
+	}
+	return core.NewSSPSolver().ComputeFlow(g)
+}
+
+func TestSlopeScalingError(t *testing.T) {
+	g := conformance.Generate(1, 4, 4)
+	r, err := NewSlopeScaling(&failingSolver{2}, 50).Search(g)
+	if err == nil || r == nil || r.Iterations != 2 {
+		t.Fatal("Wrong search with failed solve:", r, err)
+	}
+	if v := core.Verify(g, r.Flow); !v.Feasible() || math.Abs(v.Total-r.Objective) > 1e-6 {
+		t.Error("Best flow not kept:", r, v)
+	}
+	if r, err := NewSlopeScaling(&failingSolver{0}, 50).Search(g); r != nil || err == nil {
+		t.Error("Result without solve:", r, err)
+	}
+}
@@ -0,0 +1,185 @@
+// Package heuristic estimates the FCTP (fixed charge) optimum quickly, for
+// instances too large to solve exactly.
+package heuristic
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"parallax/core"
+	"parallax/fct"
+	"time"
+)
+
+// Local search over arc sets
+//
+// A state is a set of open arcs: the transport problem is solved with cost v
+// on open arcs and v + f / capacity (linear relaxation) on the others, so the
+// flow keeps to open arcs unless a new one pays its fixed cost. The start is
+// the LP relaxation flow (rounded: its arcs are open). A move closes a basic
+// arc (in use by the flow) and the solver swaps in the cheapest replacement;
+// closed arcs stay out (tabu) for Tenure iterations, arcs with a lower bound
+// are always open. After Stall iterations without improvement the best flow
+// is perturbed (iterated local search).
+
+const SOLVER_TABU = "Tabu"
+
+type TabuSolver struct {
+	Iterations int
+	Limit      time.Duration // 0 without time limit
+	Seed       int64
+	// Iterations a closed arc stays out, moves evaluated by iteration and
+	// iterations without improvement before perturbing the best flow
+	Tenure, Candidates, Stall int
+	solver                    core.Solver
+}
+
+func NewTabuSolver(iterations int, limit time.Duration, seed int64) *TabuSolver {
+	return &TabuSolver{iterations, limit, seed, 7, 10, 20, core.NewSSPSolver()}
+}
+
+// NewSolver is the heuristic by name (default limits) or the core solver.
+func NewSolver(name string) core.Solver {
+	switch name {
+	case SOLVER_TABU:
+		return NewTabuSolver(100, 10*time.Second, 1)
+	case SOLVER_SLOPE_SCALING:
+		return NewSlopeScaling(core.NewSSPSolver(), 50)
+	default:
+		return core.NewSolver(name)
+	}
+}
+
+type Result struct {
+	Flow                     []*core.EdgeFlow
+	Objective, Start         float64
+	Iterations, Improvements int
+	Time                     time.Duration
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("Objective %.2f (Start %.2f), Iterations %d, Improvements %d, Time %s", r.Objective, r.Start, r.Iterations, r.Improvements, r.Time)
+}
+
+func (s *TabuSolver) ComputeFlow(g *fct.Graph) ([]*core.EdgeFlow, error) {
+	r, err := s.Search(g)
+	if err != nil {
+		return nil, err
+	}
+	return r.Flow, nil
+}
+
+// Search is deterministic for a seed (unless stopped by the time limit).
+func (s *TabuSolver) Search(g *fct.Graph) (*Result, error) {
+	start := time.Now()
+	r := rand.New(rand.NewSource(s.Seed))
+	lp, err := s.solver.ComputeFlow(core.LinearRelaxation(g))
+	if err != nil {
+		return nil, err
+	}
+	tabu := make(map[fct.Key]int)
+	current, err := s.solve(g, lp, tabu, 0)
+	if err != nil {
+		return nil, err
+	}
+	cost := core.Objective(g, current)
+	result := &Result{Flow: current, Objective: cost, Start: cost}
+	stall := 0
+	for k := 1; k <= s.Iterations; k++ {
+		if s.Limit > 0 && time.Since(start) > s.Limit {
+			break
+		}
+		result.Iterations = k
+		var next []*core.EdgeFlow
+		var closed fct.Key
+		nextCost := math.Inf(1)
+		for _, key := range s.candidates(r, g, current) {
+			tabu[key] = k + s.Tenure
+			flow, err := s.solve(g, current, tabu, k)
+			delete(tabu, key)
+			if err != nil {
+				continue
+			}
+			if c := core.Objective(g, flow); c < nextCost {
+				next, nextCost, closed = flow, c, key
+			}
+		}
+		if next == nil {
+			break
+		}
+		tabu[closed] = k + s.Tenure
+		current, cost = next, nextCost
+		if cost < result.Objective-1e-9 {
+			result.Flow, result.Objective = current, cost
+			result.Improvements++
+			stall = 0
+			continue
+		}
+		if stall++; stall >= s.Stall {
+			current, cost = s.perturb(r, g, result.Flow, tabu, k)
+			stall = 0
+		}
+	}
+	result.Time = time.Since(start)
+	return result, nil
+}
+
+// Transport flow with the arcs of flow open and tabu arcs closed
+func (s *TabuSolver) solve(g *fct.Graph, flow []*core.EdgeFlow, tabu map[fct.Key]int, k int) ([]*core.EdgeFlow, error) {
+	open := make(map[fct.Key]bool)
+	for _, f := range flow {
+		open[fct.Key{Source: f.Source, Sink: f.Sink}] = true
+	}
+	o := g.Overlay()
+	for _, e := range g.Edges {
+		key := fct.Key{Source: e.I.Data.Id, Sink: e.J.Data.Id}
+		switch {
+		case !g.Enabled(e) || open[key] || e.Data.Lower > 0:
+		case tabu[key] > k:
+			o.Disable(key.Source, key.Sink)
+		default:
+			if c := g.Capacity(e); c > 0 {
+				o.SetCost(key.Source, key.Sink, g.Cost(e)+e.Data.FCost/c)
+			}
+		}
+	}
+	for key, until := range tabu {
+		if until <= k {
+			delete(tabu, key)
+		} else if open[key] && g.Arc(key.Source, key.Sink).Data.Lower <= 0 {
+			o.Disable(key.Source, key.Sink)
+		}
+	}
+	return s.solver.ComputeFlow(o)
+}
+
+// Arcs in use to close (without lower bound), a random sample of Candidates
+func (s *TabuSolver) candidates(r *rand.Rand, g *fct.Graph, flow []*core.EdgeFlow) []fct.Key {
+	result := make([]fct.Key, 0, s.Candidates)
+	for _, i := range r.Perm(len(flow)) {
+		if len(result) == s.Candidates {
+			break
+		}
+		if e := g.Arc(flow[i].Source, flow[i].Sink); e == nil || e.Data.Lower > 0 {
+			continue
+		}
+		result = append(result, fct.Key{Source: flow[i].Source, Sink: flow[i].Sink})
+	}
+	return result
+}
+
+// Closes a few random arcs of the best flow (tabu), the best flow if infeasible.
+func (s *TabuSolver) perturb(r *rand.Rand, g *fct.Graph, best []*core.EdgeFlow, tabu map[fct.Key]int, k int) ([]*core.EdgeFlow, float64) {
+	n := 1 + len(best)/10
+	if n > len(best) {
+		n = len(best)
+	}
+	for _, i := range r.Perm(len(best))[:n] {
+		tabu[fct.Key{Source: best[i].Source, Sink: best[i].Sink}] = k + s.Tenure
+	}
+	flow, err := s.solve(g, best, tabu, k)
+	if err != nil {
+		return best, core.Objective(g, best)
+	}
+	return flow, core.Objective(g, flow)
+}
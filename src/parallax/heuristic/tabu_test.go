@@ -0,0 +1,89 @@
+package heuristic
+
+import (
+	"math"
+	"parallax/conformance"
+	"parallax/core"
+	"parallax/fct"
+	"testing"
+	"time"
+)
+
+func TestTabuOptimum(t *testing.T) {
+	found := 0
+	for seed := int64(1); seed <= 10; seed++ {
+		g := conformance.Generate(seed, 3, 3)
+		s := NewTabuSolver(50, 0, seed)
+		r, err := s.Search(g)
+		if err != nil {
+			t.Fatal("Error searching:", err)
+		}
+		if v := core.Verify(g, r.Flow); !v.Feasible() || math.Abs(v.Total-r.Objective) > 1e-6 {
+			t.Error("Wrong flow:", r, v)
+		}
+		optimum := conformance.Optimum(g)
+		if r.Objective < optimum-1e-6 || r.Objective > r.Start+1e-6 {
+			t.Error("Wrong objective:", r, "optimum", optimum)
+		}
+		if math.Abs(r.Objective-optimum) < 1e-6 {
+			found++
+		}
+	}
+	if found < 8 {
+		t.Error("Optimum found in", found, "of 10 instances")
+	}
+}
+
+func TestTabuLowerBounds(t *testing.T) {
+	g := conformance.Generate(3, 4, 4)
+	for _, e := range g.Edges[:4] {
+		g.SetBounds(e.I.Data.Id, e.J.Data.Id, 1., math.Inf(1))
+	}
+	r, err := NewTabuSolver(30, 0, 3).Search(g)
+	if err != nil {
+		t.Fatal("Error searching:", err)
+	}
+	if v := core.Verify(g, r.Flow); !v.Feasible() {
+		t.Error("Wrong flow:", v)
+	}
+}
+
+func TestTabuLimits(t *testing.T) {
+	g, err := fct.LoadGraph("../bundle/data/N104.DAT", 0)
+	if err != nil {
+		t.Fatal("Error loading N104:", err)
+	}
+	a, err := NewTabuSolver(20, 0, 7).Search(g)
+	if err != nil {
+		t.Fatal("Error searching:", err)
+	}
+	b, _ := NewTabuSolver(20, 0, 7).Search(g)
+	if a.Objective != b.Objective || len(a.Flow) != len(b.Flow) || a.Iterations != 20 {
+		t.Error("Search not deterministic:", a, b)
+	}
+	lp := core.LinearRelaxation(g)
+	flow, _ := core.NewSSPSolver().ComputeFlow(lp)
+	if bound := core.FlowCost(lp, flow); a.Objective < bound || a.Objective > a.Start {
+		t.Error("Wrong objective:", a, "bound", bound)
+	}
+	s := NewTabuSolver(1000, time.Millisecond, 7)
+	r, err := s.Search(g)
+	if err != nil || r.Iterations == 1000 {
+		t.Error("Time limit not honored:", r, err)
+	}
+	if v := core.Verify(g, r.Flow); !v.Feasible() {
+		t.Error("Wrong flow:", v)
+	}
+}
+
+func TestNewSolver(t *testing.T) {
+	if _, ok := NewSolver(SOLVER_TABU).(*TabuSolver); !ok {
+		t.Error("Wrong solver:", SOLVER_TABU)
+	}
+	if _, ok := NewSolver(SOLVER_SLOPE_SCALING).(*SlopeScaling); !ok {
+		t.Error("Wrong solver:", SOLVER_SLOPE_SCALING)
+	}
+	if NewSolver(core.SOLVER_SSP) == nil || NewSolver("None") != nil {
+		t.Error("Wrong core solvers")
+	}
+}
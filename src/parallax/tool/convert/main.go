@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"parallax/fct"
+	"path/filepath"
+	"strings"
+)
+
+var optIn = flag.String("in", "./data/N104.DAT", "Input instance file (CSV: directory)")
+var optOut = flag.String("out", "", "Output instance file (CSV: directory), standard output if empty")
+var optFrom = flag.String("from", "", "Input format (fctp, json, csv, dimacs), by file extension if empty")
+var optTo = flag.String("to", "", "Output format (fctp, json, csv, dimacs), by file extension if empty")
+var verbose = flag.Int("verbose", 1, "Print a lot of messages, level 0, 1, 2, 3")
+
+// CSV instances are directories with these files
+var csvFiles = []string{"arcs.csv", "supply.csv", "demand.csv"}
+
+func main() {
+	flag.Parse()
+
+	from := format(*optFrom, *optIn)
+	to := format(*optTo, *optOut)
+	if to == "" && *optOut == "" {
+		to = "fctp"
+	}
+	name := instanceName(*optIn)
+
+	g, err := read(from, *optIn)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error loading file:", *optIn, err)
+		os.Exit(1)
+	}
+	if *verbose > 0 {
+		fmt.Fprintln(os.Stderr, "Loading", name+"...", g)
+	}
+	if err := write(to, *optOut, g, name); err != nil {
+		fmt.Fprintln(os.Stderr, "Error writing file:", *optOut, err)
+		os.Exit(1)
+	}
+}
+
+func format(name, path string) string {
+	if name != "" {
+		return strings.ToLower(name)
+	}
+	path = strings.ToLower(strings.TrimSuffix(path, ".gz"))
+	switch {
+	case strings.HasSuffix(path, ".dat"):
+		return "fctp"
+	case strings.HasSuffix(path, ".json"):
+		return "json"
+	case strings.HasSuffix(path, ".min"), strings.HasSuffix(path, ".dimacs"):
+		return "dimacs"
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return "csv"
+	}
+	return ""
+}
+
+func instanceName(path string) string {
+	name := filepath.Base(strings.TrimSuffix(path, ".gz"))
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+func read(format, path string) (*fct.Graph, error) {
+	if format == "fctp" {
+		return fct.LoadGraph(path, *verbose)
+	}
+	if format == "csv" {
+		files := make([]io.Reader, len(csvFiles))
+		for i, name := range csvFiles {
+			file, err := os.Open(filepath.Join(path, name))
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+			files[i] = file
+		}
+		return fct.ReadCSV(files[0], files[1], files[2])
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	switch format {
+	case "json":
+		return fct.ReadJSON(file)
+	case "dimacs":
+		return fct.ReadDIMACS(file)
+	}
+	return nil, fmt.Errorf("Unknown input format '%s'", format)
+}
+
+func write(format, path string, g *fct.Graph, name string) error {
+	if format == "csv" {
+		if path == "" {
+			return fmt.Errorf("CSV output requires a directory")
+		}
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return err
+		}
+		files := make([]io.Writer, len(csvFiles))
+		for i, name := range csvFiles {
+			file, err := os.Create(filepath.Join(path, name))
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			files[i] = file
+		}
+		return fct.WriteCSV(files[0], files[1], files[2], g)
+	}
+	var writeGraph func(w io.Writer) error
+	switch format {
+	case "fctp":
+		writeGraph = func(w io.Writer) error { return fct.WriteGraph(w, g, name) }
+	case "json":
+		writeGraph = func(w io.Writer) error { return fct.WriteJSON(w, g, name) }
+	case "dimacs":
+		writeGraph = func(w io.Writer) error { return fct.WriteDIMACS(w, g, name) }
+	default:
+		// before creating (truncating) the output file
+		return fmt.Errorf("Unknown output format '%s'", format)
+	}
+	if path == "" {
+		return writeGraph(os.Stdout)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeGraph(file)
+}
@@ -3,16 +3,19 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"parallax/core"
 	"parallax/engine"
 	"parallax/fct"
 	"runtime"
 )
 
-var optEngine = flag.String("name", engine.BID_RANDOM_EDGES, "Engine Name (RandomEdges, FirstEdges, ...)")
+var optEngine = flag.String("name", engine.BID_RANDOM_EDGES, "Engine Name (RandomEdges, FirstEdges, GurobiEdges, <Solver>Edges e.g. VogelEdges, SlopeScalingEdges, TabuEdges)")
 var optFactor = flag.Float64("factor", 2., "Price multiplication factor (Variable cost)")
+var optPenalty = flag.Float64("balance", 0., "Penalty cost for dummy source/sink on unbalanced instances (0 disabled)")
 var optFile = flag.String("instance", "./data/N104.DAT", "FCTP data file name")
 var optThreads = flag.Int("threads", runtime.NumCPU(), "Number of system threads")
+var optDot = flag.String("dot", "", "Write the instance and streams in GraphViz DOT format to file")
 var verbose = flag.Int("verbose", 1, "Print a lot of messages, level 0, 1, 2, 3")
 
 func main() {
@@ -39,6 +42,9 @@ func main() {
 		fmt.Println("Error loading engine:", *optEngine)
 		return
 	}
+	if b, ok := n.(core.Balanced); ok && *optPenalty > 0 {
+		b.SetBalance(*optPenalty)
+	}
 	k := int(20 * g.Size() / 100)
 	if k < 1 {
 		k = 1
@@ -63,4 +69,12 @@ func main() {
 	for _, s := range r.Streams {
 		fmt.Println(s)
 	}
+	if *optDot != "" {
+		err := fct.WriteFile(*optDot, func(w io.Writer) error {
+			return core.WriteStreamDot(w, g, gname, r)
+		})
+		if err != nil {
+			fmt.Println("Error writing file:", *optDot, err)
+		}
+	}
 }
@@ -3,11 +3,18 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"parallax/core"
 	"parallax/fct"
+	"parallax/heuristic"
+	"time"
 )
 
 var optFile = flag.String("instance", "./data/N104.DAT", "FCTP data file name")
+var optSolver = flag.String("solver", core.SOLVER_GUROBI, "Solver Name (Gurobi, SSP, CostScaling, NorthwestCorner, LeastCost, Vogel, FixedCharge, Tabu, SlopeScaling)")
+var optPenalty = flag.Float64("balance", 0., "Penalty cost for dummy source/sink on unbalanced instances (0 disabled)")
+var optDot = flag.String("dot", "", "Write the instance and flow in GraphViz DOT format to file")
+var optFlow = flag.String("flow", "", "Write the flow to file (see tool/verify)")
 var verbose = flag.Int("verbose", 1, "Print a lot of messages, level 0, 1, 2, 3")
 
 func main() {
@@ -20,13 +27,38 @@ func main() {
 		fmt.Println("Error loading file:", *optFile, err)
 		return
 	}
-	s := core.NewGurobiSolver()
+	s := heuristic.NewSolver(*optSolver)
+	if s == nil {
+		fmt.Println("Error loading solver:", *optSolver)
+		return
+	}
+	if *optPenalty > 0 {
+		s = core.NewBalancedSolver(s, *optPenalty)
+	}
+	start := time.Now()
 	r, err := s.ComputeFlow(g)
 	if err != nil {
 		fmt.Println("Error computing flow:", *optFile, err)
 		return
 	}
+	fmt.Printf("%s: cost %.2f, objective %.2f, %d edges, %s\n", *optSolver, core.FlowCost(g, r), core.Objective(g, r), len(r), time.Since(start))
 	for _, f := range r {
 		fmt.Println(f)
 	}
+	if *optDot != "" {
+		err := fct.WriteFile(*optDot, func(w io.Writer) error {
+			return core.WriteFlowDot(w, g, *optFile, r)
+		})
+		if err != nil {
+			fmt.Println("Error writing file:", *optDot, err)
+		}
+	}
+	if *optFlow != "" {
+		err := fct.WriteFile(*optFlow, func(w io.Writer) error {
+			return core.WriteFlow(w, r)
+		})
+		if err != nil {
+			fmt.Println("Error writing file:", *optFlow, err)
+		}
+	}
 }
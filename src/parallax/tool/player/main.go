@@ -1,23 +1,66 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	_ "parallax/bundle"
 	"parallax/core"
 	"parallax/engine"
 	"parallax/fct"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
 )
 
 var optName = flag.String("name", "Parallax", "Player Name")
 var optServer = flag.String("server", "localhost:8080", "Game server")
-var optData = flag.String("data", "./data", "Directory with FCTP data files")
+var optData = flag.String("data", "./data", "FCTP data files: directory, zip://file.zip, tar://file.tar.gz or embed://")
+var optCache = flag.Int("cache", 0, "Maximum number of instances in memory (0 unbounded)")
 var optPreload = flag.Bool("load", true, "Load all data files (instances)")
 var optThreads = flag.Int("threads", runtime.NumCPU(), "Number of system threads")
-var optEngine = flag.String("engine", engine.BID_GUROBI_EDGES, "Engine Name (RandomEdges, FirstEdges, ...)")
+var optEngine = flag.String("engine", engine.BID_GUROBI_EDGES, "Engine Name (RandomEdges, FirstEdges, GurobiEdges, <Solver>Edges e.g. VogelEdges, SlopeScalingEdges, TabuEdges)")
 var optFactor = flag.Float64("factor", 2., "Price multiplication factor (Variable cost)")
+var optPenalty = flag.Float64("balance", 0., "Penalty cost for dummy source/sink on unbalanced instances (0 disabled)")
+var optResults = flag.Int("results", 0, "Solver result cache size, repeated problems are not solved again (0 disabled)")
+var optResultsFile = flag.String("results-file", "", "File keeping the solver result cache between runs")
+var optMetrics = flag.String("metrics", "", "Metrics HTTP address, e.g. localhost:9090 (disabled if empty)")
+var optLogs = flag.String("logs", "", "Directory for one log file per game (prefixed stdout if empty)")
+var optGames gameFlags
 var verbose = flag.Int("verbose", 1, "Print a lot of messages, level 0, 1, 2, 3")
 
+func init() {
+	flag.Var(&optGames, "game", "Game as name@server/engine, repeat for concurrent games (default from -name, -server, -engine)")
+}
+
+type gameSpec struct {
+	name, server, engine string
+}
+
+type gameFlags []*gameSpec
+
+func (f *gameFlags) String() string {
+	out := make([]string, len(*f))
+	for i, g := range *f {
+		out[i] = g.name + "@" + g.server + "/" + g.engine
+	}
+	return strings.Join(out, " ")
+}
+
+func (f *gameFlags) Set(value string) error {
+	at := strings.Index(value, "@")
+	slash := strings.LastIndex(value, "/")
+	if at < 1 || slash < at+2 || slash == len(value)-1 {
+		return fmt.Errorf("Wrong game format (name@server/engine): %s", value)
+	}
+	*f = append(*f, &gameSpec{value[:at], value[at+1 : slash], value[slash+1:]})
+	return nil
+}
+
 func main() {
 	fmt.Println("Parallax Engine: Game Theory Player")
 
@@ -26,17 +69,137 @@ func main() {
 	fmt.Println("Threads:", *optThreads)
 	runtime.GOMAXPROCS(*optThreads)
 
-	graphs := fct.NewFileLoader(*optData, *verbose)
+	graphs, err := fct.OpenLoader(*optData, *verbose, *optCache)
+	if err != nil {
+		fmt.Println("Error opening data:", *optData, err)
+		os.Exit(1)
+	}
 	if *optPreload {
-		graphs.LoadAll()
+		m, err := graphs.LoadAll(*optThreads)
+		if *verbose > 0 && m != nil {
+			fmt.Print(m)
+		}
+		if err != nil {
+			fmt.Println("Error loading instances:", err)
+			os.Exit(1)
+		}
+	}
+
+	var results *core.ResultCache
+	if *optResults > 0 {
+		results = core.NewResultCache(*optResults)
+		if *optResultsFile != "" {
+			if err := results.Load(*optResultsFile); err != nil {
+				fmt.Println("Error loading results:", *optResultsFile, err)
+			}
+		}
+	}
+
+	var metrics *core.Metrics
+	if *optMetrics != "" {
+		metrics = core.NewMetrics(graphs)
+		if results != nil {
+			metrics.SetResultCache(results)
+		}
+		http.Handle("/metrics", metrics)
+		go func() {
+			fmt.Println("Metrics:", "http://"+*optMetrics+"/metrics")
+			if err := http.ListenAndServe(*optMetrics, nil); err != nil {
+				fmt.Println("Error serving metrics:", err)
+			}
+		}()
 	}
 
-	n := engine.New(*optEngine, graphs, *optFactor)
-	if n == nil {
-		fmt.Println("Error loading engine:", *optEngine)
-		return
+	games := optGames
+	if len(games) == 0 {
+		games = gameFlags{&gameSpec{*optName, *optServer, *optEngine}}
 	}
 
-	h := core.NewHandler(*optName, n, *verbose)
-	h.Connect(*optServer)
+	s := core.NewSupervisor()
+	for i, spec := range games {
+		// metrics and log are kept by game tag, names can repeat
+		tag := fmt.Sprintf("%d-%s", i+1, spec.name)
+		var log io.Writer
+		var loader fct.GraphLoader = graphs
+		if len(games) > 1 || *optLogs != "" {
+			w, err := gameLog(tag)
+			if err != nil {
+				fmt.Println("Error creating log:", tag, err)
+				return
+			}
+			defer w.Close()
+			log = w
+			loader = graphs.WithLog(w)
+		}
+		n := engine.New(spec.engine, loader, *optFactor)
+		if n == nil {
+			fmt.Println("Error loading engine:", spec.engine)
+			return
+		}
+		if b, ok := n.(core.Balanced); ok && *optPenalty > 0 {
+			b.SetBalance(*optPenalty)
+		}
+		h := core.NewHandler(spec.name, n, *verbose)
+		if metrics != nil {
+			h.SetMetrics(metrics, tag)
+		}
+		// after metrics, solver time is recorded on cache misses only
+		if c, ok := n.(core.Cached); ok && results != nil {
+			c.SetResultCache(results)
+		}
+		g := s.Add(spec.server, h, log)
+		fmt.Println("Game:", g, spec.engine)
+	}
+	s.Run()
+
+	fmt.Println("Instance cache:", graphs.Stats())
+	if results != nil {
+		fmt.Println("Solver cache:", results.Stats())
+		if *optResultsFile != "" {
+			if err := results.Save(*optResultsFile); err != nil {
+				fmt.Println("Error saving results:", *optResultsFile, err)
+			}
+		}
+	}
+}
+
+func gameLog(tag string) (io.WriteCloser, error) {
+	if *optLogs == "" {
+		return &prefixWriter{prefix: "[" + tag + "] ", out: os.Stdout}, nil
+	}
+	return os.Create(filepath.Join(*optLogs, tag+".log"))
+}
+
+// Complete lines from concurrent games are written one at a time.
+var stdoutLock sync.Mutex
+
+type prefixWriter struct {
+	prefix string
+	out    io.Writer
+	buf    bytes.Buffer
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buf.Next(i + 1)
+		stdoutLock.Lock()
+		_, err := fmt.Fprint(w.out, w.prefix, string(line))
+		stdoutLock.Unlock()
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *prefixWriter) Close() error {
+	if w.buf.Len() > 0 {
+		w.Write([]byte("\n"))
+	}
+	return nil
 }
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	_ "parallax/bundle"
+	"parallax/core"
+	"parallax/fct"
+	"parallax/heuristic"
+	"runtime"
+)
+
+var optFile = flag.String("instance", "", "FCTP data file name (all instances from -data if empty)")
+var optData = flag.String("data", "./data", "FCTP data files: directory, zip://file.zip, tar://file.tar.gz or embed://")
+var optLP = flag.Bool("lp", true, "Solve the linear relaxation (Gurobi)")
+var optBounds = flag.Int("bounds", 0, "Lagrangian bound iterations, gap to the slope scaling incumbent (0 disabled)")
+var optThreads = flag.Int("threads", runtime.NumCPU(), "Number of system threads")
+var verbose = flag.Int("verbose", 0, "Print a lot of messages, level 0, 1, 2, 3")
+
+func main() {
+	fmt.Println("Parallax Engine: Stats Tool")
+
+	flag.Parse()
+
+	runtime.GOMAXPROCS(*optThreads)
+
+	if *optFile != "" {
+		g, err := fct.LoadGraph(*optFile, *verbose)
+		if err != nil {
+			fmt.Println("Error loading file:", *optFile, err)
+			os.Exit(1)
+		}
+		stats(*optFile, g)
+		return
+	}
+
+	graphs, err := fct.OpenLoader(*optData, *verbose, 0)
+	if err != nil {
+		fmt.Println("Error opening data:", *optData, err)
+		os.Exit(1)
+	}
+	m, err := graphs.LoadAll(*optThreads)
+	if m == nil {
+		fmt.Println("Error loading instances:", err)
+		os.Exit(1)
+	}
+	// unbalanced or infeasible instances are described too (Check error)
+	for _, info := range m.Instances {
+		g := graphs.Instance(info.Name)
+		if g == nil {
+			fmt.Println(info.Name, "Error:", info.Err)
+			continue
+		}
+		stats(info.Name, g)
+	}
+}
+
+func stats(name string, g *fct.Graph) {
+	fmt.Println("Instance", name)
+	fmt.Print(g.Stats())
+	if err := g.Check(); err != nil {
+		fmt.Println("Check:", err)
+	}
+	if *optLP {
+		lp := core.LinearRelaxation(g)
+		flow, err := core.NewGurobiSolver().ComputeFlow(lp)
+		if err != nil {
+			fmt.Println("LP: Error computing flow:", err)
+		} else {
+			fmt.Printf("LP: optimum %.2f, arcs %d, objective %.2f\n", core.FlowCost(lp, flow), len(flow), core.Objective(g, flow))
+		}
+	}
+	if *optBounds > 0 {
+		bounds(g)
+	}
+	fmt.Println()
+}
+
+func bounds(g *fct.Graph) {
+	r, err := heuristic.NewSlopeScaling(core.NewSSPSolver(), 50).Search(g)
+	if err != nil {
+		fmt.Println("Bounds: Error computing incumbent:", err)
+		return
+	}
+	if b, err := core.LagrangianBound(g, r.Objective, *optBounds); err != nil {
+		fmt.Println("Bounds: Error computing Lagrangian bound:", err)
+	} else {
+		fmt.Println(b)
+	}
+	if *optLP {
+		if b, err := core.StrongLPBound(g, r.Objective); err != nil {
+			fmt.Println("Bounds: Error computing strong LP bound:", err)
+		} else {
+			fmt.Println(b)
+		}
+	}
+}
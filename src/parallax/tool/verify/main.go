@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"parallax/core"
+	"parallax/fct"
+)
+
+var optFile = flag.String("instance", "./data/N104.DAT", "FCTP data file name")
+var optFlow = flag.String("flow", "-", "Flow file, one edge per line: source sink amount, or game result streams (- for stdin)")
+var verbose = flag.Int("verbose", 0, "Print a lot of messages, level 0, 1, 2, 3")
+
+func main() {
+	fmt.Println("Parallax Engine: Verify Tool")
+
+	flag.Parse()
+
+	g, err := fct.LoadGraph(*optFile, *verbose)
+	if err != nil {
+		fmt.Println("Error loading file:", *optFile, err)
+		os.Exit(1)
+	}
+	var r io.Reader = os.Stdin
+	if *optFlow != "-" {
+		file, err := os.Open(*optFlow)
+		if err != nil {
+			fmt.Println("Error opening file:", *optFlow, err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		r = file
+	}
+	flow, err := core.ReadFlow(r)
+	if err != nil {
+		fmt.Println("Error reading flow:", *optFlow, err)
+		os.Exit(1)
+	}
+	v := core.Verify(g, flow)
+	fmt.Print(v)
+	if !v.Feasible() {
+		os.Exit(2)
+	}
+}